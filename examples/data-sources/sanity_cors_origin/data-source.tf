@@ -0,0 +1,4 @@
+data "sanity_cors_origin" "website" {
+  project = "project-id"
+  origin  = "https://example.com"
+}
@@ -0,0 +1,9 @@
+data "sanity_cors_origins" "all" {
+  project = "project-id"
+}
+
+# Only the origins that are allowed to send credentials
+data "sanity_cors_origins" "with_credentials" {
+  project           = "project-id"
+  allow_credentials = true
+}
@@ -0,0 +1,5 @@
+data "sanity_current_user" "me" {}
+
+output "current_user_id" {
+  value = data.sanity_current_user.me.id
+}
@@ -0,0 +1,4 @@
+data "sanity_dataset" "production" {
+  project = "project-id"
+  name    = "production"
+}
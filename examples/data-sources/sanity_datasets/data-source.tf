@@ -0,0 +1,3 @@
+data "sanity_datasets" "all" {
+  project = "project-id"
+}
@@ -0,0 +1,9 @@
+data "sanity_groq" "post_count" {
+  project = "project-id"
+  dataset = "production"
+  query   = "count(*[_type == \"post\"])"
+}
+
+output "post_count" {
+  value = jsondecode(data.sanity_groq.post_count.result)
+}
@@ -0,0 +1,8 @@
+data "sanity_organization" "acme" {
+  slug = "acme"
+}
+
+resource "sanity_project" "website" {
+  name         = "Website"
+  organization = data.sanity_organization.acme.id
+}
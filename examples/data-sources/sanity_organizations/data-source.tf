@@ -0,0 +1 @@
+data "sanity_organizations" "all" {}
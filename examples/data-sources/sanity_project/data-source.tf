@@ -1,3 +1,9 @@
 data "sanity_project" "main" {
   id = "project-id"
 }
+
+# Look a project up by its name instead of its ID
+data "sanity_project" "blog" {
+  name         = "Blog"
+  organization = "organization-id"
+}
@@ -0,0 +1,3 @@
+data "sanity_project_roles" "all" {
+  project = "project-id"
+}
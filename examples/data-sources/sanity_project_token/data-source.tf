@@ -0,0 +1,4 @@
+data "sanity_project_token" "deployer" {
+  project = "project-id"
+  label   = "Deployer token"
+}
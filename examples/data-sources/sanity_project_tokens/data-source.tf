@@ -0,0 +1,7 @@
+data "sanity_project_tokens" "all" {
+  project = "project-id"
+}
+
+output "token_labels" {
+  value = [for t in data.sanity_project_tokens.all.tokens : t.label]
+}
@@ -0,0 +1,7 @@
+data "sanity_projects" "all" {
+  organization = "organization-id"
+}
+
+output "project_ids" {
+  value = [for p in data.sanity_projects.all.projects : p.id]
+}
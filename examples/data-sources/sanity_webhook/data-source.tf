@@ -0,0 +1,4 @@
+data "sanity_webhook" "publish" {
+  project = "project-id"
+  name    = "Publish notifications"
+}
@@ -0,0 +1,3 @@
+data "sanity_webhooks" "all" {
+  project = "project-id"
+}
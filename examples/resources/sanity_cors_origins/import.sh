@@ -0,0 +1,4 @@
+# Import using the project ID, which can be found 
+# on the project page under https://sanity.io/manage.
+# All of the CORS origins of the project are imported.
+terraform import sanity_cors_origins.default project-id
@@ -0,0 +1,18 @@
+resource "sanity_cors_origins" "default" {
+  project = sanity_project.default.id
+
+  origin {
+    origin            = sanity_project.default.studio_url
+    allow_credentials = true
+  }
+
+  origin {
+    origin            = "https://example.com"
+    allow_credentials = false
+  }
+
+  origin {
+    origin            = "http://localhost:3333"
+    allow_credentials = true
+  }
+}
@@ -1,5 +1,21 @@
 resource "sanity_dataset" "production" {
-  project  = var.project_id
-  name     = "production"
-  acl_mode = "public"
+  project     = var.project_id
+  name        = "production"
+  acl_mode    = "public"
+  description = "Content served by the public website"
+}
+
+resource "sanity_dataset" "staging" {
+  project   = var.project_id
+  name      = "staging"
+  acl_mode  = "private"
+  copy_from = sanity_dataset.production.name
+}
+
+# Changing the name copies the dataset instead of replacing it with an empty one
+resource "sanity_dataset" "archive" {
+  project      = var.project_id
+  name         = "archive-2024"
+  acl_mode     = "private"
+  allow_rename = true
 }
@@ -0,0 +1,4 @@
+# Import using the project ID and dataset name.
+# The project ID can be found on the project 
+# page under https://sanity.io/manage.
+terraform import sanity_dataset_acl.default project-id/dataset-name
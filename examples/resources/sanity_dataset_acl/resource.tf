@@ -0,0 +1,14 @@
+resource "sanity_dataset_acl" "internal" {
+  project = var.project_id
+  dataset = "internal"
+
+  grant {
+    role       = "viewer"
+    permission = "read"
+  }
+
+  grant {
+    role       = "editor"
+    permission = "write"
+  }
+}
@@ -1,3 +1,9 @@
 # import using the project ID, which can be found 
 # on the project page under https://sanity.io/manage
 terraform import sanity_project.default project-id
+
+# Only the project itself is imported. To find the
+# datasets and CORS origins to import alongside it,
+# read them with the sanity_datasets and
+# sanity_cors_origins data sources, then import each
+# one with its own import ID (e.g. project-id/name).
@@ -2,4 +2,33 @@ resource "sanity_project" "main" {
   name        = "Test project"
   studio_host = "my-test-project"
   color       = "#0000ff"
+
+  metadata = {
+    team = "content"
+  }
+}
+
+# A project that is created together with its datasets
+resource "sanity_project" "blog" {
+  name = "Blog"
+
+  dataset {
+    name     = "production"
+    acl_mode = "public"
+  }
+
+  dataset {
+    name     = "staging"
+    acl_mode = "private"
+  }
+}
+
+# A project that is usable right away; the dataset is only created once
+resource "sanity_project" "docs" {
+  name = "Docs"
+
+  initial_dataset {
+    name     = "production"
+    acl_mode = "public"
+  }
 }
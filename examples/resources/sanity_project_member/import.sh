@@ -0,0 +1,4 @@
+# Import using the project ID and the Sanity user ID of the member.
+# The project ID can be found on the project 
+# page under https://sanity.io/manage.
+terraform import sanity_project_member.default project-id/member-id
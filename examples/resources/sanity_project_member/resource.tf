@@ -0,0 +1,5 @@
+resource "sanity_project_member" "editor" {
+  project   = var.project_id
+  member_id = "p1a2b3c4d"
+  role      = "editor"
+}
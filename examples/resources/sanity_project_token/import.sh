@@ -0,0 +1,4 @@
+# Import using the project ID and token ID.
+# The token ID is listed by the sanity_project_tokens
+# data source. The token key cannot be imported.
+terraform import sanity_project_token.deployer project-id/token-id
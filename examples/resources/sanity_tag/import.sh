@@ -0,0 +1,4 @@
+# Import using the project ID and tag name.
+# The project ID can be found on the project 
+# page under https://sanity.io/manage.
+terraform import sanity_tag.default project-id/tag-name
@@ -0,0 +1,5 @@
+resource "sanity_tag" "marketing" {
+  project = var.project_id
+  name    = "marketing"
+  title   = "Marketing"
+}
@@ -0,0 +1,4 @@
+# Import using the project ID and webhook ID.
+# The project ID can be found on the project 
+# page under https://sanity.io/manage.
+terraform import sanity_webhook.default project-id/webhook-id
@@ -0,0 +1,20 @@
+resource "sanity_webhook" "publish" {
+  project     = var.project_id
+  type        = "document"
+  name        = "Publish notifications"
+  url         = "https://example.com/hooks/sanity"
+  dataset     = "production"
+  description = "Notifies the site when posts are published."
+  http_method = "POST"
+  secret      = var.webhook_secret
+
+  headers = {
+    "X-Source" = "sanity"
+  }
+
+  rule {
+    on         = ["create", "update"]
+    filter     = "_type == 'post'"
+    projection = "{_id, title}"
+  }
+}
@@ -12,6 +12,9 @@ type defaultValueAttributePlanModifier struct {
 	DefaultValue attr.Value
 }
 
+// DefaultValue returns a plan modifier that sets an attribute that is not
+// configured to the given value. The value must match the attribute type, such
+// as a types.String for a string attribute.
 func DefaultValue(v attr.Value) tfsdk.AttributePlanModifier {
 	return &defaultValueAttributePlanModifier{v}
 }
@@ -23,7 +26,7 @@ func (apm *defaultValueAttributePlanModifier) Description(ctx context.Context) s
 }
 
 func (apm *defaultValueAttributePlanModifier) MarkdownDescription(ctx context.Context) string {
-	return fmt.Sprintf("Sets the default value %q (%s) if the attribute is not set", apm.DefaultValue, apm.DefaultValue.Type(ctx))
+	return fmt.Sprintf("Sets the default value %s (%s) if the attribute is not set", apm.DefaultValue, apm.DefaultValue.Type(ctx))
 }
 
 func (apm *defaultValueAttributePlanModifier) Modify(_ context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
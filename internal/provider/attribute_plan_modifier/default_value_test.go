@@ -0,0 +1,51 @@
+package attribute_plan_modifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDefaultValue(t *testing.T) {
+	cases := []struct {
+		name         string
+		defaultValue attr.Value
+		config       attr.Value
+		want         attr.Value
+	}{
+		{name: "string null", defaultValue: types.String{Value: "a"}, config: types.String{Null: true}, want: types.String{Value: "a"}},
+		{name: "string unknown", defaultValue: types.String{Value: "a"}, config: types.String{Unknown: true}, want: types.String{Unknown: true}},
+		{name: "string set", defaultValue: types.String{Value: "a"}, config: types.String{Value: "b"}, want: types.String{Value: "b"}},
+		{name: "string set to default", defaultValue: types.String{Value: "a"}, config: types.String{Value: "a"}, want: types.String{Value: "a"}},
+		{name: "bool null", defaultValue: types.Bool{Value: true}, config: types.Bool{Null: true}, want: types.Bool{Value: true}},
+		{name: "bool unknown", defaultValue: types.Bool{Value: true}, config: types.Bool{Unknown: true}, want: types.Bool{Unknown: true}},
+		{name: "bool set", defaultValue: types.Bool{Value: true}, config: types.Bool{Value: false}, want: types.Bool{Value: false}},
+		{name: "bool set to default", defaultValue: types.Bool{Value: true}, config: types.Bool{Value: true}, want: types.Bool{Value: true}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// the framework plans the configured value, which is null for an
+			// attribute that is not configured
+			req := tfsdk.ModifyAttributePlanRequest{
+				AttributePath:   path.Root("test"),
+				AttributeConfig: tc.config,
+				AttributePlan:   tc.config,
+			}
+			resp := tfsdk.ModifyAttributePlanResponse{AttributePlan: tc.config}
+			DefaultValue(tc.defaultValue).Modify(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+			}
+			if !resp.AttributePlan.Equal(tc.want) {
+				t.Fatalf("expected plan %s, got %s", tc.want, resp.AttributePlan)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package attribute_plan_modifier
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type envDefaultBoolAttributePlanModifier struct {
+	EnvVar   string
+	Fallback bool
+}
+
+// EnvDefaultBool returns a plan modifier that sets a bool attribute that is
+// not configured to the value of the environment variable, or to the fallback
+// when the variable is unset or empty. The variable is read at plan time.
+func EnvDefaultBool(envVar string, fallback bool) tfsdk.AttributePlanModifier {
+	return &envDefaultBoolAttributePlanModifier{envVar, fallback}
+}
+
+var _ tfsdk.AttributePlanModifier = (*envDefaultBoolAttributePlanModifier)(nil)
+
+func (apm *envDefaultBoolAttributePlanModifier) Description(ctx context.Context) string {
+	return apm.MarkdownDescription(ctx)
+}
+
+func (apm *envDefaultBoolAttributePlanModifier) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Sets the value of the %s environment variable, or %t if it is not set, if the attribute is not set", apm.EnvVar, apm.Fallback)
+}
+
+func (apm *envDefaultBoolAttributePlanModifier) Modify(_ context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
+	if !req.AttributeConfig.IsNull() {
+		return
+	}
+
+	v, ok := os.LookupEnv(apm.EnvVar)
+	if !ok || v == "" {
+		res.AttributePlan = types.Bool{Value: apm.Fallback}
+		return
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		res.Diagnostics.AddAttributeError(
+			req.AttributePath,
+			"Invalid Environment Variable",
+			fmt.Sprintf("The %s environment variable must be a boolean such as true or false, got: %q", apm.EnvVar, v),
+		)
+		return
+	}
+
+	res.AttributePlan = types.Bool{Value: b}
+}
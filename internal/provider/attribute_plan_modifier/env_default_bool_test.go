@@ -0,0 +1,67 @@
+package attribute_plan_modifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestEnvDefaultBool(t *testing.T) {
+	const envVar = "TEST_ENV_DEFAULT_BOOL"
+
+	cases := []struct {
+		name     string
+		env      *string
+		config   types.Bool
+		fallback bool
+		want     attr.Value
+		wantErr  bool
+	}{
+		{name: "unset", config: types.Bool{Null: true}, fallback: true, want: types.Bool{Value: true}},
+		{name: "empty", env: ptr(""), config: types.Bool{Null: true}, fallback: true, want: types.Bool{Value: true}},
+		{name: "true", env: ptr("true"), config: types.Bool{Null: true}, want: types.Bool{Value: true}},
+		{name: "one", env: ptr("1"), config: types.Bool{Null: true}, want: types.Bool{Value: true}},
+		{name: "false", env: ptr("false"), config: types.Bool{Null: true}, fallback: true, want: types.Bool{Value: false}},
+		{name: "invalid", env: ptr("yes"), config: types.Bool{Null: true}, want: types.Bool{Unknown: true}, wantErr: true},
+		{name: "configured", env: ptr("true"), config: types.Bool{Value: false}, want: types.Bool{Value: false}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != nil {
+				t.Setenv(envVar, *tc.env)
+			}
+
+			// the framework plans the configured value, or unknown for a
+			// computed attribute that is not configured
+			plan := attr.Value(types.Bool{Unknown: true})
+			if !tc.config.Null {
+				plan = tc.config
+			}
+
+			req := tfsdk.ModifyAttributePlanRequest{
+				AttributePath:   path.Root("test"),
+				AttributeConfig: tc.config,
+				AttributePlan:   plan,
+			}
+			resp := tfsdk.ModifyAttributePlanResponse{AttributePlan: plan}
+			EnvDefaultBool(envVar, tc.fallback).Modify(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tc.wantErr {
+				t.Fatalf("expected an error: %t, got diagnostics: %v", tc.wantErr, resp.Diagnostics)
+			}
+			if !resp.AttributePlan.Equal(tc.want) {
+				t.Fatalf("expected plan %s, got %s", tc.want, resp.AttributePlan)
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
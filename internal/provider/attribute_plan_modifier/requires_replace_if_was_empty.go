@@ -0,0 +1,50 @@
+package attribute_plan_modifier
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type requiresReplaceIfWasEmptyAttributePlanModifier struct{}
+
+// RequiresReplaceIfWasEmpty returns a plan modifier for string attributes that
+// may be set once but never changed afterwards. Setting the value when the
+// prior state is null or empty is an in-place update; changing a value that
+// was already set forces a replacement.
+func RequiresReplaceIfWasEmpty() tfsdk.AttributePlanModifier {
+	return &requiresReplaceIfWasEmptyAttributePlanModifier{}
+}
+
+var _ tfsdk.AttributePlanModifier = (*requiresReplaceIfWasEmptyAttributePlanModifier)(nil)
+
+func (apm *requiresReplaceIfWasEmptyAttributePlanModifier) Description(ctx context.Context) string {
+	return apm.MarkdownDescription(ctx)
+}
+
+func (apm *requiresReplaceIfWasEmptyAttributePlanModifier) MarkdownDescription(ctx context.Context) string {
+	return "Requires replacement if the value changes after it has been set"
+}
+
+func (apm *requiresReplaceIfWasEmptyAttributePlanModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest, res *tfsdk.ModifyAttributePlanResponse) {
+	// nothing to replace on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var state, plan types.String
+	res.Diagnostics.Append(tfsdk.ValueAs(ctx, req.AttributeState, &state)...)
+	res.Diagnostics.Append(tfsdk.ValueAs(ctx, res.AttributePlan, &plan)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+
+	if state.Null || state.Value == "" || plan.Unknown {
+		return
+	}
+
+	if !plan.Equal(state) {
+		res.RequiresReplace = true
+	}
+}
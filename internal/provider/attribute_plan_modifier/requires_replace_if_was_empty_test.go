@@ -0,0 +1,81 @@
+package attribute_plan_modifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRequiresReplaceIfWasEmpty(t *testing.T) {
+	schema := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"test": {Optional: true, Type: types.StringType},
+		},
+	}
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{"test": tftypes.String}}
+
+	// raw returns the object with the value of the attribute, or a null object
+	// for a resource that does not exist
+	raw := func(v *types.String) tftypes.Value {
+		if v == nil {
+			return tftypes.NewValue(objectType, nil)
+		}
+		value, err := v.ToTerraformValue(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{"test": value})
+	}
+
+	cases := []struct {
+		name  string
+		state *types.String
+		plan  *types.String
+		want  bool
+	}{
+		{name: "create", plan: &types.String{Value: "a"}},
+		{name: "destroy", state: &types.String{Value: "a"}},
+		{name: "first set from null", state: &types.String{Null: true}, plan: &types.String{Value: "a"}},
+		{name: "first set from empty", state: &types.String{Value: ""}, plan: &types.String{Value: "a"}},
+		{name: "unchanged", state: &types.String{Value: "a"}, plan: &types.String{Value: "a"}},
+		{name: "changed", state: &types.String{Value: "a"}, plan: &types.String{Value: "b"}, want: true},
+		{name: "removed", state: &types.String{Value: "a"}, plan: &types.String{Null: true}, want: true},
+		{name: "unknown", state: &types.String{Value: "a"}, plan: &types.String{Unknown: true}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := tfsdk.ModifyAttributePlanRequest{
+				AttributePath: path.Root("test"),
+				State:         tfsdk.State{Schema: schema, Raw: raw(tc.state)},
+				Plan:          tfsdk.Plan{Schema: schema, Raw: raw(tc.plan)},
+			}
+			if tc.state != nil {
+				req.AttributeState = *tc.state
+			} else {
+				req.AttributeState = types.String{Null: true}
+			}
+			if tc.plan != nil {
+				req.AttributePlan = *tc.plan
+			} else {
+				req.AttributePlan = types.String{Null: true}
+			}
+			req.AttributeConfig = req.AttributePlan
+
+			resp := tfsdk.ModifyAttributePlanResponse{AttributePlan: req.AttributePlan}
+			RequiresReplaceIfWasEmpty().Modify(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected error diagnostics: %v", resp.Diagnostics)
+			}
+			if resp.RequiresReplace != tc.want {
+				t.Fatalf("expected RequiresReplace %t, got %t", tc.want, resp.RequiresReplace)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package attribute_validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type mapKeysNotInAttributeValidator struct {
+	Keys    []string
+	Message string
+}
+
+// MapKeysNotIn returns a validator that rejects maps with any of the keys. The
+// message explains why the keys are not allowed and is included in the
+// diagnostic.
+func MapKeysNotIn(keys []string, message string) tfsdk.AttributeValidator {
+	return &mapKeysNotInAttributeValidator{keys, message}
+}
+
+var _ tfsdk.AttributeValidator = (*mapKeysNotInAttributeValidator)(nil)
+
+func (av *mapKeysNotInAttributeValidator) Description(ctx context.Context) string {
+	return av.MarkdownDescription(ctx)
+}
+
+func (av *mapKeysNotInAttributeValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Map keys must not be any of `%s`", strings.Join(av.Keys, "`, `"))
+}
+
+func (av *mapKeysNotInAttributeValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, res *tfsdk.ValidateAttributeResponse) {
+	var value types.Map
+	res.Diagnostics.Append(tfsdk.ValueAs(ctx, req.AttributeConfig, &value)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+
+	if value.Null || value.Unknown {
+		return
+	}
+
+	for _, key := range av.Keys {
+		if _, ok := value.Elems[key]; ok {
+			res.Diagnostics.AddAttributeError(
+				req.AttributePath,
+				"Invalid Attribute Value",
+				fmt.Sprintf("%s, got key: %q", av.Message, key),
+			)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package attribute_validator
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type stringMatchesAttributeValidator struct {
+	Regexp  *regexp.Regexp
+	Message string
+}
+
+// StringMatches returns a validator that rejects string values that do not
+// match the regular expression. The message describes the expected format and
+// is included in the diagnostic.
+func StringMatches(re *regexp.Regexp, message string) tfsdk.AttributeValidator {
+	return &stringMatchesAttributeValidator{re, message}
+}
+
+var _ tfsdk.AttributeValidator = (*stringMatchesAttributeValidator)(nil)
+
+func (av *stringMatchesAttributeValidator) Description(ctx context.Context) string {
+	return av.MarkdownDescription(ctx)
+}
+
+func (av *stringMatchesAttributeValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("Value must match the regular expression `%s`", av.Regexp)
+}
+
+func (av *stringMatchesAttributeValidator) Validate(ctx context.Context, req tfsdk.ValidateAttributeRequest, res *tfsdk.ValidateAttributeResponse) {
+	var value types.String
+	res.Diagnostics.Append(tfsdk.ValueAs(ctx, req.AttributeConfig, &value)...)
+	if res.Diagnostics.HasError() {
+		return
+	}
+
+	if value.Null || value.Unknown {
+		return
+	}
+
+	if !av.Regexp.MatchString(value.Value) {
+		res.Diagnostics.AddAttributeError(
+			req.AttributePath,
+			"Invalid Attribute Value",
+			fmt.Sprintf("%s, got: %q", av.Message, value.Value),
+		)
+	}
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// cliConfig holds the settings that the Sanity CLI stores after `sanity login`.
+type cliConfig struct {
+	AuthToken    string `json:"authToken"`
+	ApiURL       string `json:"apiUrl"`
+	Organization string `json:"organization"`
+}
+
+// cliConfigPath returns the path of the Sanity CLI configuration file. The CLI
+// keeps it in the XDG config directory on every platform, so
+// os.UserConfigDir, which differs on macOS and Windows, is not used.
+func cliConfigPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "sanity", "config.json"), nil
+}
+
+// readCLIConfig reads the Sanity CLI configuration file at path.
+func readCLIConfig(path string) (*cliConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config cliConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// loadCLIConfig reads the Sanity CLI configuration file from its default
+// location.
+func loadCLIConfig() (*cliConfig, error) {
+	path, err := cliConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	return readCLIConfig(path)
+}
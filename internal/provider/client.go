@@ -0,0 +1,194 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// Client is the client shared by all resources and data sources. It embeds
+// the go-sanity client and adds the parts of the Sanity HTTP API that
+// go-sanity does not cover yet.
+//
+// Terraform runs the operations of independent resources concurrently, so a
+// Client must be safe for concurrent use. Its fields are only set while the
+// provider is configured and are read-only afterwards. The transports of the
+// HTTP client keep no state between requests, except for the rate limiter and
+// the oauth2 token source, which guard their state with a mutex. Per-request
+// settings, such as a token override, travel in the request context.
+type Client struct {
+	*sanity.Client
+
+	httpClient *http.Client
+
+	baseURL string
+
+	// defaultOrganization is the organization that projects are created in
+	// when they do not specify one.
+	defaultOrganization string
+
+	// defaultCORSAllowCredentials is the allow_credentials of CORS origins
+	// that do not set it.
+	defaultCORSAllowCredentials bool
+
+	// readOnly is set when the resources must not change anything in Sanity.
+	readOnly bool
+}
+
+const (
+	defaultBaseURL     = "https://api.sanity.io"
+	defaultBaseURLHost = "api.sanity.io"
+)
+
+// NewClient creates a new Client that sends its requests to `baseURL`.
+//
+// If `baseURL` is empty, the production Sanity API is used. The `httpClient`
+// is expected to provide authentication.
+func NewClient(httpClient *http.Client, baseURL string) (*Client, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute URL", baseURL)
+	}
+
+	c := *httpClient
+	c.Transport = &apiErrorTransport{next: &loggingTransport{next: transportOrDefault(c.Transport)}}
+	if baseURL != defaultBaseURL {
+		c.Transport = &baseURLTransport{baseURL: u, next: c.Transport}
+	}
+	httpClient = &c
+
+	return &Client{
+		Client:     sanity.NewClient(httpClient),
+		httpClient: httpClient,
+		baseURL:    baseURL,
+	}, nil
+}
+
+func (c *Client) do(ctx context.Context, url string, method string, body any, result any) error {
+	_, err := c.doWithHeader(ctx, url, method, body, result)
+	return err
+}
+
+// doWithHeader works like do and also returns the response header.
+func (c *Client) doWithHeader(ctx context.Context, url string, method string, body any, result any) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode > 299 {
+		return nil, newAPIError(resp)
+	}
+
+	return resp.Header, json.NewDecoder(resp.Body).Decode(result)
+}
+
+// listAll fetches every page of a list endpoint. Pages are linked with a
+// `Link` header whose `rel` is `next`; an endpoint that does not paginate
+// is fetched in a single request.
+func listAll[T any](ctx context.Context, c *Client, pageURL string) ([]T, error) {
+	var all []T
+
+	for pageURL != "" {
+		var page []T
+		header, err := c.doWithHeader(ctx, pageURL, http.MethodGet, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+
+		pageURL, err = nextPageURL(pageURL, header)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return all, nil
+}
+
+// nextPageURL returns the URL of the next page from the `Link` header, or an
+// empty string on the last page.
+func nextPageURL(pageURL string, header http.Header) (string, error) {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		target, params, ok := strings.Cut(link, ";")
+		if !ok || !strings.Contains(params, `rel="next"`) {
+			continue
+		}
+
+		target = strings.Trim(strings.TrimSpace(target), "<>")
+		base, err := url.Parse(pageURL)
+		if err != nil {
+			return "", err
+		}
+		next, err := base.Parse(target)
+		if err != nil {
+			return "", err
+		}
+		return next.String(), nil
+	}
+
+	return "", nil
+}
+
+const (
+	findRetryAttempts  = 3
+	findRetryBaseDelay = 1 * time.Second
+)
+
+// findWithRetry calls find until it reports a match. Right after a resource is
+// created, a list can briefly leave it out, so a miss is retried with
+// exponential backoff before it is reported. Errors are returned immediately.
+func findWithRetry(ctx context.Context, find func() (bool, error)) (bool, error) {
+	delay := findRetryBaseDelay
+
+	for attempt := 0; ; attempt++ {
+		found, err := find()
+		if err != nil || found || attempt >= findRetryAttempts {
+			return found, err
+		}
+
+		tflog.Debug(ctx, "sanity list did not include the resource, retrying", map[string]interface{}{
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
@@ -0,0 +1,16 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// ListCORSEntries fetches and returns all CORS entries of the project,
+// following pagination.
+func (c *Client) ListCORSEntries(ctx context.Context, projectId string) ([]sanity.CORSEntry, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/cors", c.baseURL, projectId)
+
+	return listAll[sanity.CORSEntry](ctx, c, url)
+}
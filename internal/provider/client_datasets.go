@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+type UpdateDatasetRequest struct {
+	// AclMode describes whether the dataset is accessible publicly or privately.
+	// If available privately, the data in the dataset is only accessible via a
+	// token.
+	AclMode string `json:"aclMode,omitempty"`
+}
+
+// UpdateDataset applies the requested changes to the specified dataset.
+func (c *Client) UpdateDataset(ctx context.Context, projectId string, datasetName string, r *UpdateDatasetRequest) (*sanity.Dataset, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/datasets/%s", c.baseURL, projectId, datasetName)
+
+	type response struct {
+		Name    string `json:"datasetName"`
+		AclMode string `json:"aclMode"`
+	}
+
+	var resp response
+	err := c.do(ctx, url, http.MethodPatch, r, &resp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &sanity.Dataset{Name: resp.Name, AclMode: resp.AclMode}, nil
+}
+
+const (
+	DatasetPermissionRead  = "read"
+	DatasetPermissionWrite = "write"
+)
+
+// DatasetGrant gives the members and tokens with a role access to a dataset.
+type DatasetGrant struct {
+	// Role is the name of the role that is granted access.
+	Role string `json:"role"`
+
+	// Permission is either `read` or `write`.
+	Permission string `json:"permission"`
+}
+
+// ListDatasetGrants fetches and returns the role grants on the dataset.
+func (c *Client) ListDatasetGrants(ctx context.Context, projectId string, datasetName string) ([]DatasetGrant, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/datasets/%s/grants", c.baseURL, projectId, datasetName)
+
+	var grants []DatasetGrant
+	err := c.do(ctx, url, http.MethodGet, nil, &grants)
+
+	return grants, err
+}
+
+// SetDatasetGrants replaces the role grants on the dataset. Passing no grants
+// removes all of them.
+func (c *Client) SetDatasetGrants(ctx context.Context, projectId string, datasetName string, grants []DatasetGrant) ([]DatasetGrant, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/datasets/%s/grants", c.baseURL, projectId, datasetName)
+
+	type request struct {
+		Grants []DatasetGrant `json:"grants"`
+	}
+
+	if grants == nil {
+		grants = []DatasetGrant{}
+	}
+
+	var resp []DatasetGrant
+	err := c.do(ctx, url, http.MethodPut, &request{Grants: grants}, &resp)
+
+	return resp, err
+}
+
+// ListDatasets fetches and returns all datasets of the project, following
+// pagination.
+func (c *Client) ListDatasets(ctx context.Context, projectId string) ([]sanity.Dataset, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/datasets", c.baseURL, projectId)
+
+	return listAll[sanity.Dataset](ctx, c, url)
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	JobStatePending   = "pending"
+	JobStateRunning   = "running"
+	JobStateCompleted = "completed"
+	JobStateFailed    = "failed"
+)
+
+// Job describes a long-running task, such as copying a dataset.
+type Job struct {
+	Id string `json:"id"`
+
+	// State is one of `pending`, `running`, `completed`, or `failed`.
+	State string `json:"state"`
+
+	// Progress is the percentage of the work that is done.
+	Progress int `json:"progress"`
+}
+
+// GetJob fetches the current state of a job.
+func (c *Client) GetJob(ctx context.Context, jobId string) (*Job, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/jobs/%s", c.baseURL, jobId)
+
+	var job Job
+	err := c.do(ctx, url, http.MethodGet, nil, &job)
+
+	return &job, err
+}
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// AddMemberRole assigns the role to the specified user on the project. The
+// user is added as a project member if they are not one already.
+func (c *Client) AddMemberRole(ctx context.Context, projectId string, userId string, roleName string) error {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/members/%s/roles/%s", c.baseURL, projectId, userId, roleName)
+
+	var x any
+	return c.do(ctx, url, http.MethodPut, nil, &x)
+}
+
+// RemoveMemberRole removes the role from the specified project member.
+func (c *Client) RemoveMemberRole(ctx context.Context, projectId string, userId string, roleName string) (bool, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/members/%s/roles/%s", c.baseURL, projectId, userId, roleName)
+
+	type response struct {
+		Deleted bool `json:"deleted"`
+	}
+
+	var resp response
+	err := c.do(ctx, url, http.MethodDelete, nil, &resp)
+
+	return resp.Deleted, err
+}
+
+// RemoveMember removes the specified member from the project without prompt.
+func (c *Client) RemoveMember(ctx context.Context, projectId string, userId string) (bool, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/members/%s", c.baseURL, projectId, userId)
+
+	type response struct {
+		Deleted bool `json:"deleted"`
+	}
+
+	var resp response
+	err := c.do(ctx, url, http.MethodDelete, nil, &resp)
+
+	return resp.Deleted, err
+}
+
+// Invitation is a request for a user to join a project. Some roles are only
+// granted once the user accepts the invitation.
+type Invitation struct {
+	Id         string `json:"id"`
+	Email      string `json:"email"`
+	Role       string `json:"role"`
+	UserId     string `json:"userId"`
+	IsAccepted bool   `json:"isAccepted"`
+	IsRevoked  bool   `json:"isRevoked"`
+}
+
+// ListInvitations fetches the invitations that have been sent for the project.
+func (c *Client) ListInvitations(ctx context.Context, projectId string) ([]Invitation, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/invitations/project/%s", c.baseURL, projectId)
+
+	var invitations []Invitation
+	err := c.do(ctx, url, http.MethodGet, nil, &invitations)
+
+	return invitations, err
+}
+
+// RevokeInvitation cancels an invitation that has not been accepted yet.
+func (c *Client) RevokeInvitation(ctx context.Context, projectId string, invitationId string) error {
+	url := fmt.Sprintf("%s/v2021-06-07/invitations/project/%s/%s", c.baseURL, projectId, invitationId)
+
+	var x any
+	return c.do(ctx, url, http.MethodDelete, nil, &x)
+}
@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Organization describes a Sanity organization that projects can belong to.
+type Organization struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// ListOrganizations fetches and returns a list of all organizations that the
+// authenticated user is a member of.
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/organizations", c.baseURL)
+
+	var organizations []Organization
+	err := c.do(ctx, url, http.MethodGet, nil, &organizations)
+
+	return organizations, err
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// TransferProject moves the project to another organization.
+func (c *Client) TransferProject(ctx context.Context, projectId string, organizationId string) (*sanity.Project, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s", c.baseURL, projectId)
+
+	type request struct {
+		OrganizationId string `json:"organizationId"`
+	}
+
+	var project sanity.Project
+	err := c.do(ctx, url, http.MethodPatch, &request{OrganizationId: organizationId}, &project)
+
+	return &project, err
+}
+
+// UpdateProjectMetadata sets the metadata entries of the project. An entry
+// with an empty value is removed, which resets it to the Sanity default.
+// go-sanity only sets the color and external studio host, and leaves empty
+// values out of project updates, so it cannot be used for other entries or
+// to clear them.
+func (c *Client) UpdateProjectMetadata(ctx context.Context, projectId string, metadata map[string]string) (*sanity.Project, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s", c.baseURL, projectId)
+
+	type request struct {
+		Metadata map[string]string `json:"metadata"`
+	}
+
+	var project sanity.Project
+	err := c.do(ctx, url, http.MethodPatch, &request{Metadata: metadata}, &project)
+
+	return &project, err
+}
+
+// Project is a project as the API returns it. go-sanity does not decode the
+// `updatedAt` of a project.
+type Project struct {
+	sanity.Project
+
+	// UpdatedAt is when the project was last modified.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// GetProject fetches a project by its ID, including when it was last modified.
+func (c *Client) GetProject(ctx context.Context, projectId string) (*Project, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s", c.baseURL, projectId)
+
+	var project Project
+	err := c.do(ctx, url, http.MethodGet, nil, &project)
+
+	return &project, err
+}
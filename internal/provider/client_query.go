@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// Query runs a GROQ query against the dataset and returns the JSON result.
+//
+// Queries are served from the project API host, `https://<projectId>.api.sanity.io`,
+// which is derived from the base URL of the client. The query endpoint cannot
+// change any documents.
+func (c *Client) Query(ctx context.Context, projectId string, dataset string, query string) (json.RawMessage, error) {
+	u, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s://%s.%s%s/v2021-06-07/data/query/%s?query=%s", u.Scheme, projectId, u.Host, u.Path, dataset, url.QueryEscape(query))
+
+	type response struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	var resp response
+	err = c.do(ctx, endpoint, http.MethodGet, nil, &resp)
+
+	return resp.Result, err
+}
@@ -0,0 +1,28 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// A ProjectRole extends the go-sanity role with its display-friendly title.
+type ProjectRole struct {
+	sanity.ProjectRole
+
+	// Title is the display-friendly name of the role (e.g., `Administrator`).
+	Title string `json:"title"`
+}
+
+// ListProjectRoles fetches and returns the roles associated with the specified
+// project, including custom roles.
+func (c *Client) ListProjectRoles(ctx context.Context, projectId string) ([]ProjectRole, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/roles", c.baseURL, projectId)
+
+	var roles []ProjectRole
+	err := c.do(ctx, url, http.MethodGet, nil, &roles)
+
+	return roles, err
+}
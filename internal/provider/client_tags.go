@@ -0,0 +1,19 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// GetTag fetches a tag by its name.
+func (c *Client) GetTag(ctx context.Context, projectId string, tagIdentifier string) (*sanity.DatasetTag, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/projects/%s/tags/%s", c.baseURL, projectId, tagIdentifier)
+
+	var tag sanity.DatasetTag
+	err := c.do(ctx, url, http.MethodGet, nil, &tag)
+
+	return &tag, err
+}
@@ -0,0 +1,125 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tessellator/go-sanity/sanity"
+	"golang.org/x/oauth2"
+)
+
+func TestClient_pagination(t *testing.T) {
+	m := newMockSanity(t)
+	m.pageSize = 2
+	m.addProject("p1", "Test")
+	m.withProject("p1", func(p *mockProject) {
+		p.cors = nil
+		for i := int64(1); i <= 5; i++ {
+			p.cors = append(p.cors, sanity.CORSEntry{Id: i, Origin: fmt.Sprintf("https://%d.example.com", i), ProjectId: "p1"})
+			p.datasets = append(p.datasets, sanity.Dataset{Name: fmt.Sprintf("dataset%d", i), AclMode: "public"})
+		}
+	})
+
+	client := m.client(t)
+	ctx := context.Background()
+
+	entries, err := client.ListCORSEntries(ctx, "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 5 || entries[4].Origin != "https://5.example.com" {
+		t.Fatalf("expected all 5 CORS entries, got %v", entries)
+	}
+	if n := len(m.requestsTo("GET", "/projects/p1/cors")); n != 3 {
+		t.Fatalf("expected 3 pages of CORS entries, got %d requests", n)
+	}
+
+	datasets, err := client.ListDatasets(ctx, "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(datasets) != 5 || datasets[4].Name != "dataset5" {
+		t.Fatalf("expected all 5 datasets, got %v", datasets)
+	}
+	if n := len(m.requestsTo("GET", "/projects/p1/datasets")); n != 3 {
+		t.Fatalf("expected 3 pages of datasets, got %d requests", n)
+	}
+}
+
+// TestClient_concurrentUse drives concurrent requests through a client with
+// the transports that Configure sets up. Run it with -race to check that the
+// client is safe for concurrent use.
+func TestClient_concurrentUse(t *testing.T) {
+	m := newMockSanity(t)
+	m.pageSize = 2
+	m.addProject("p1", "Test")
+	m.withProject("p1", func(p *mockProject) {
+		for i := int64(101); i <= 105; i++ {
+			p.cors = append(p.cors, sanity.CORSEntry{Id: i, Origin: fmt.Sprintf("https://%d.example.com", i), ProjectId: "p1"})
+		}
+	})
+
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: mockToken}),
+			Base:   &tokenOverrideTransport{next: http.DefaultTransport},
+		},
+	}
+	httpClient.Transport = &userAgentTransport{userAgent: userAgent("test"), next: httpClient.Transport}
+	httpClient.Transport = &rateLimitTransport{limiter: newRateLimiter(1000), next: httpClient.Transport}
+	httpClient.Transport = &retryTransport{maxRetries: defaultMaxRetries, timeout: defaultRequestTimeout, next: httpClient.Transport}
+
+	client, err := NewClient(httpClient, m.server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*n)
+	for i := 0; i < n; i++ {
+		i := i
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			entries, err := client.ListCORSEntries(context.Background(), "p1")
+			if err == nil && len(entries) != 5 {
+				err = fmt.Errorf("expected 5 CORS entries, got %d", len(entries))
+			}
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			// half of the requests override the token of the provider
+			ctx := context.Background()
+			if i%2 == 0 {
+				ctx = contextWithToken(ctx, types.String{Value: mockToken})
+			}
+			_, err := client.Projects.CreateDataset(ctx, "p1", &sanity.CreateDatasetRequest{
+				Name:    fmt.Sprintf("dataset%d", i),
+				AclMode: "public",
+			})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	var datasets int
+	m.withProject("p1", func(p *mockProject) {
+		datasets = len(p.datasets)
+	})
+	if datasets != n {
+		t.Fatalf("expected %d datasets, got %d", n, datasets)
+	}
+}
@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// CurrentUser describes the user that the token belongs to. For a project
+// token, this is the robot user that Sanity created for the token.
+type CurrentUser struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+
+	// Roles are the roles that the user has been assigned.
+	Roles []sanity.Role `json:"roles"`
+}
+
+// GetCurrentUser fetches the user that is authenticated by the token.
+func (c *Client) GetCurrentUser(ctx context.Context) (*CurrentUser, error) {
+	url := fmt.Sprintf("%s/v2021-06-07/users/me", c.baseURL)
+
+	var user CurrentUser
+	err := c.do(ctx, url, http.MethodGet, nil, &user)
+
+	return &user, err
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+const (
+	WebhookTypeDocument    = "document"
+	WebhookTypeTransaction = "transaction"
+)
+
+// A Webhook sends an HTTP request to a URL whenever content in a dataset
+// changes.
+//
+// Refer to https://www.sanity.io/docs/webhooks for more information.
+type Webhook struct {
+	// Id is the unique identifier for the webhook.
+	Id string `json:"id,omitempty"`
+
+	// Type is either `document` or `transaction`.
+	Type string `json:"type"`
+
+	// Name is the user-friendly name for the webhook.
+	Name string `json:"name"`
+
+	// URL is the address that receives the webhook requests.
+	URL string `json:"url"`
+
+	// Dataset is the name of the dataset the webhook listens to, or `*` for all
+	// datasets.
+	Dataset string `json:"dataset"`
+
+	// Description is a short text describing the webhook. It is a pointer so
+	// that an update can remove it by sending an empty string.
+	Description *string `json:"description,omitempty"`
+
+	// IsDisabledByUser indicates whether the webhook has been disabled.
+	IsDisabledByUser bool `json:"isDisabledByUser"`
+
+	// ApiVersion is the API version used for the filter and projection.
+	ApiVersion string `json:"apiVersion,omitempty"`
+
+	// HttpMethod is the HTTP method of the webhook requests. Defaults to
+	// `POST`.
+	HttpMethod string `json:"httpMethod,omitempty"`
+
+	// Headers are additional HTTP headers sent with the webhook requests. It is
+	// a pointer so that an update can remove all the headers by sending an
+	// empty map.
+	Headers *map[string]string `json:"headers,omitempty"`
+
+	// Secret is used to sign the webhook requests. It is never returned by the
+	// API. It is a pointer so that an update can remove it by sending an empty
+	// string.
+	Secret *string `json:"secret,omitempty"`
+
+	// Rule describes which document changes trigger the webhook. Rules only
+	// apply to document webhooks.
+	Rule *WebhookRule `json:"rule,omitempty"`
+}
+
+// A WebhookRule describes which document changes trigger a webhook.
+type WebhookRule struct {
+	// On is the list of operations (`create`, `update`, `delete`) that trigger
+	// the webhook.
+	On []string `json:"on,omitempty"`
+
+	// Filter is a GROQ filter that documents must match. It is a pointer so
+	// that an update can remove it by sending an empty string.
+	Filter *string `json:"filter,omitempty"`
+
+	// Projection is a GROQ projection that shapes the webhook payload. It is a
+	// pointer so that an update can remove it by sending an empty string.
+	Projection *string `json:"projection,omitempty"`
+}
+
+// ListWebhooks fetches and returns all the webhooks for the specified project.
+func (c *Client) ListWebhooks(ctx context.Context, projectId string) ([]Webhook, error) {
+	url := fmt.Sprintf("%s/v2021-10-04/hooks/projects/%s", c.baseURL, projectId)
+
+	var webhooks []Webhook
+	err := c.do(ctx, url, http.MethodGet, nil, &webhooks)
+
+	return webhooks, err
+}
+
+// GetWebhook fetches a webhook by its unique identifier.
+func (c *Client) GetWebhook(ctx context.Context, projectId string, webhookId string) (*Webhook, error) {
+	url := fmt.Sprintf("%s/v2021-10-04/hooks/projects/%s/%s", c.baseURL, projectId, webhookId)
+
+	var webhook Webhook
+	err := c.do(ctx, url, http.MethodGet, nil, &webhook)
+
+	return &webhook, err
+}
+
+// CreateWebhook adds a new webhook to the specified project.
+func (c *Client) CreateWebhook(ctx context.Context, projectId string, w *Webhook) (*Webhook, error) {
+	url := fmt.Sprintf("%s/v2021-10-04/hooks/projects/%s", c.baseURL, projectId)
+
+	var webhook Webhook
+	err := c.do(ctx, url, http.MethodPost, w, &webhook)
+
+	return &webhook, err
+}
+
+// UpdateWebhook replaces the configuration of the specified webhook.
+func (c *Client) UpdateWebhook(ctx context.Context, projectId string, webhookId string, w *Webhook) (*Webhook, error) {
+	url := fmt.Sprintf("%s/v2021-10-04/hooks/projects/%s/%s", c.baseURL, projectId, webhookId)
+
+	var webhook Webhook
+	err := c.do(ctx, url, http.MethodPatch, w, &webhook)
+
+	return &webhook, err
+}
+
+// DeleteWebhook removes the specified webhook without prompt.
+func (c *Client) DeleteWebhook(ctx context.Context, projectId string, webhookId string) (bool, error) {
+	url := fmt.Sprintf("%s/v2021-10-04/hooks/projects/%s/%s", c.baseURL, projectId, webhookId)
+
+	type response struct {
+		Id      string `json:"id"`
+		Deleted bool   `json:"deleted"`
+	}
+
+	var resp response
+	err := c.do(ctx, url, http.MethodDelete, nil, &resp)
+
+	return resp.Deleted, err
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &CORSOriginDataSource{}
+
+func NewCORSOriginDataSource() datasource.DataSource {
+	return &CORSOriginDataSource{}
+}
+
+// CORSOriginDataSource defines the data source implementation.
+type CORSOriginDataSource struct {
+	client *Client
+}
+
+// CORSOriginDataSourceModel describes the data source data model.
+type CORSOriginDataSourceModel struct {
+	Id               types.String `tfsdk:"id"`
+	Project          types.String `tfsdk:"project"`
+	Origin           types.String `tfsdk:"origin"`
+	AllowCredentials types.Bool   `tfsdk:"allow_credentials"`
+}
+
+func (d *CORSOriginDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cors_origin"
+}
+
+func (d *CORSOriginDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets a CORS origin configured for a Sanity project by its origin.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "The unique CORS entry ID generated by Sanity.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"project": {
+				MarkdownDescription: "The ID of the project that the CORS origin belongs to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"origin": {
+				MarkdownDescription: "The origin to look up (e.g. `https://example.com`). The origin must match exactly.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"allow_credentials": {
+				MarkdownDescription: "Indicates whether the origin may send authenticated requests with a token or session cookie.",
+				Type:                types.BoolType,
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (d *CORSOriginDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *CORSOriginDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CORSOriginDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	entries, err := d.client.ListCORSEntries(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	for _, entry := range entries {
+		if entry.Origin == data.Origin.Value {
+			data.Id = types.String{Value: fmt.Sprintf("%d", entry.Id)}
+			data.AllowCredentials = types.Bool{Value: entry.AllowCredentials}
+
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			return
+		}
+	}
+
+	resp.Diagnostics.AddError("cors origin not found", fmt.Sprintf("No CORS origin %q was found in project %s", data.Origin.Value, data.Project.Value))
+}
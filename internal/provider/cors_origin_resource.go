@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -10,26 +11,30 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/tessellator/go-sanity/sanity"
-	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_plan_modifier"
 )
 
 var _ resource.Resource = &CORSOriginResource{}
 var _ resource.ResourceWithImportState = &CORSOriginResource{}
+var _ resource.ResourceWithUpgradeState = &CORSOriginResource{}
+var _ resource.ResourceWithModifyPlan = &CORSOriginResource{}
 
 func NewCORSOriginResource() resource.Resource {
 	return &CORSOriginResource{}
 }
 
 type CORSOriginResource struct {
-	client *sanity.Client
+	client *Client
 }
 
 type CORSOriginResourceModel struct {
 	Id               types.String `tfsdk:"id"`
 	Origin           types.String `tfsdk:"origin"`
 	AllowCredentials types.Bool   `tfsdk:"allow_credentials"`
+	AutoCreated      types.Bool   `tfsdk:"auto_created"`
 	Project          types.String `tfsdk:"project"`
+	Token            types.String `tfsdk:"token"`
 }
 
 func (r *CORSOriginResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -38,9 +43,16 @@ func (r *CORSOriginResource) Metadata(ctx context.Context, req resource.Metadata
 
 func (r *CORSOriginResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
-		MarkdownDescription: "Provides a CORS origin to a Sanity project. A CORS origin is a host that can connect to the Sanity Project API.",
+		MarkdownDescription: "Provides a CORS origin to a Sanity project. A CORS origin is a host that can connect to the Sanity Project API.\n\nSanity creates a CORS entry for the `studio_host` of a project. If the origin is that entry and it has the same `allow_credentials`, the entry is adopted instead of creating a duplicate. Any other existing entry for the origin is a conflict and must be imported.\n\nThe ID of the resource is the numeric ID of the CORS entry in Sanity and does not depend on the resource address, so the resource can be renamed with a `moved` block without being replaced.",
+		Version:             1,
 
 		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this CORS entry instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
 			"id": {
 				Computed:            true,
 				MarkdownDescription: "The unique ID for the CORS origin.",
@@ -62,11 +74,19 @@ func (r *CORSOriginResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.
 				Computed: true,
 				PlanModifiers: tfsdk.AttributePlanModifiers{
 					resource.RequiresReplace(),
-					attribute_plan_modifier.DefaultValue(types.Bool{Value: true}),
+					resource.UseStateForUnknown(),
 				},
-				MarkdownDescription: "Indicates whether the origin is allowed to send credentials (e.g. a session cookie or an authorization token). Defaults to `true`.",
+				MarkdownDescription: "Indicates whether the origin is allowed to send credentials (e.g. a session cookie or an authorization token). Defaults to the `default_cors_allow_credentials` of the provider, which defaults to `true`. The Sanity API does not support updating a CORS origin, so changing this value will force a replacement.",
 				Type:                types.BoolType,
 			},
+			"auto_created": {
+				Computed:            true,
+				MarkdownDescription: "Indicates whether the origin is the one Sanity creates for the `studio_host` of the project. The studio hosted by Sanity needs this origin, so destroying the resource breaks the studio. Sanity does not record who created an entry, so this is derived from the origin and the current studio host of the project.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+				Type: types.BoolType,
+			},
 			"project": {
 				Required:            true,
 				MarkdownDescription: "The ID of the project that the CORS origin belongs to.",
@@ -85,12 +105,12 @@ func (r *CORSOriginResource) Configure(ctx context.Context, req resource.Configu
 		return
 	}
 
-	client, ok := req.ProviderData.(*sanity.Client)
+	client, ok := req.ProviderData.(*Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -99,7 +119,30 @@ func (r *CORSOriginResource) Configure(ctx context.Context, req resource.Configu
 	r.client = client
 }
 
+// ModifyPlan plans the provider default for the allow_credentials of a new CORS
+// origin that does not set it. The default is unknown until the provider is
+// configured, in which case it is applied in Create.
+func (r *CORSOriginResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if !req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var allowCredentials types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("allow_credentials"), &allowCredentials)...)
+
+	if resp.Diagnostics.HasError() || !allowCredentials.Null {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("allow_credentials"), r.client.defaultCORSAllowCredentials)...)
+}
+
 func (r *CORSOriginResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *CORSOriginResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -108,22 +151,73 @@ func (r *CORSOriginResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity cors entry", map[string]interface{}{"project": data.Project.Value, "origin": data.Origin.Value})
+
+	allowCredentials := r.client.defaultCORSAllowCredentials
+	if !data.AllowCredentials.IsNull() && !data.AllowCredentials.IsUnknown() {
+		allowCredentials = data.AllowCredentials.Value
+	}
+
+	// Sanity creates a CORS entry for the studio host of a project, so an entry
+	// for that origin may already exist. It is adopted rather than duplicated,
+	// while any other existing entry is a conflict.
+	entries, err := r.client.ListCORSEntries(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+	for _, e := range entries {
+		if e.Origin != data.Origin.Value {
+			continue
+		}
+
+		project, err := r.client.Projects.Get(ctx, data.Project.Value)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
+		if !corsOriginAutoCreated(e.Origin, project) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("origin"),
+				"CORS origin already exists",
+				fmt.Sprintf("The origin %s already exists in project %s (id %d). Import it with the ID %s/%s, or delete the existing entry.", e.Origin, data.Project.Value, e.Id, data.Project.Value, e.Origin),
+			)
+			return
+		}
+		if e.AllowCredentials != allowCredentials {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("allow_credentials"),
+				"CORS origin already exists",
+				fmt.Sprintf("The origin %s already exists in project %s (id %d) with allow_credentials set to %t. Set allow_credentials to match, or delete the existing entry.", e.Origin, data.Project.Value, e.Id, e.AllowCredentials),
+			)
+			return
+		}
+
+		tflog.Info(ctx, "adopted the sanity cors entry of the studio host", map[string]interface{}{"id": e.Id, "origin": e.Origin})
+
+		data.Id = types.String{Value: fmt.Sprintf("%d", e.Id)}
+		data.AllowCredentials = types.Bool{Value: e.AllowCredentials}
+		data.AutoCreated = types.Bool{Value: true}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
 	corsReq := &sanity.CreateCORSEntryRequest{
 		Origin:           data.Origin.Value,
-		AllowCredentials: sanity.NewBool(true),
-	}
-	if !data.AllowCredentials.IsNull() {
-		corsReq.AllowCredentials = sanity.NewBool(data.AllowCredentials.Value)
+		AllowCredentials: sanity.NewBool(allowCredentials),
 	}
 	entry, err := r.client.Projects.CreateCORSEntry(ctx, data.Project.Value, corsReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
 	data.Id = types.String{Value: fmt.Sprintf("%d", entry.Id)}
 	data.AllowCredentials = types.Bool{Value: entry.AllowCredentials}
 
+	resp.Diagnostics.Append(r.readAutoCreated(ctx, data)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
@@ -137,6 +231,9 @@ func (r *CORSOriginResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity cors entry", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Entry id is null", "Entry id is null")
 		return
@@ -146,44 +243,84 @@ func (r *CORSOriginResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
-	entries, err := r.client.Projects.ListCORSEntries(ctx, data.Project.Value)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
-		return
-	}
+	rawId, diags := parseCORSEntryId(data.Id.Value)
+	resp.Diagnostics.Append(diags...)
 
-	rawId := int64(0)
-	_, err = fmt.Sscanf(data.Id.Value, "%d", &rawId)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
 	var entry sanity.CORSEntry
-	found := false
-	for _, e := range entries {
-		if e.Id == rawId {
-			entry = e
-			found = true
-			break
+	found, err := findWithRetry(ctx, func() (bool, error) {
+		entries, err := r.client.ListCORSEntries(ctx, data.Project.Value)
+		if err != nil {
+			return false, err
 		}
+		for _, e := range entries {
+			if e.Id == rawId {
+				entry = e
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
 	}
 	if !found {
-		resp.Diagnostics.AddError("cors entry not found", "cors entry not found")
+		// the entry was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
 	data.AllowCredentials = types.Bool{Value: entry.AllowCredentials}
 	data.Origin = types.String{Value: entry.Origin}
 
+	resp.Diagnostics.Append(r.readAutoCreated(ctx, data)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// readAutoCreated refreshes whether the origin is the one Sanity creates for
+// the studio host of the project.
+func (r *CORSOriginResource) readAutoCreated(ctx context.Context, data *CORSOriginResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	project, err := r.client.Projects.Get(ctx, data.Project.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	data.AutoCreated = types.Bool{Value: corsOriginAutoCreated(data.Origin.Value, project)}
+
+	return diags
+}
+
 func (r *CORSOriginResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("Provider Error", "Update is not supported on CORS entry")
+	// All attributes but the token force a replacement because the Sanity API
+	// has no endpoint for updating a CORS entry, so only the token can change.
+	var data *CORSOriginResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *CORSOriginResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *CORSOriginResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -192,6 +329,9 @@ func (r *CORSOriginResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity cors entry", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Entry id is null", "Entry id is null")
 		return
@@ -201,17 +341,17 @@ func (r *CORSOriginResource) Delete(ctx context.Context, req resource.DeleteRequ
 		return
 	}
 
-	rawId := int64(0)
-	_, err := fmt.Sscanf(data.Id.Value, "%d", &rawId)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+	rawId, diags := parseCORSEntryId(data.Id.Value)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	_, err = r.client.Projects.DeleteCORSEntry(ctx, data.Project.Value, rawId)
+	_, err := r.client.Projects.DeleteCORSEntry(ctx, data.Project.Value, rawId)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("entry %s could not be deleted, got error: %s", data.Id.Value, err))
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "entry %s could not be deleted", data.Id.Value))
 		return
 	}
 }
@@ -223,7 +363,7 @@ func (r *CORSOriginResource) ImportState(ctx context.Context, req resource.Impor
 		return
 	}
 
-	entries, err := r.client.Projects.ListCORSEntries(ctx, projectId)
+	entries, err := r.client.ListCORSEntries(ctx, projectId)
 	if err != nil {
 		resp.Diagnostics.AddError("Import Error", err.Error())
 		return
@@ -239,3 +379,74 @@ func (r *CORSOriginResource) ImportState(ctx context.Context, req resource.Impor
 
 	resp.Diagnostics.AddError("Import Error", "The requested CORS origin was not found")
 }
+
+// corsOriginResourceModelV0 is the model of version 0 of the schema.
+type corsOriginResourceModelV0 struct {
+	Id               types.String `tfsdk:"id"`
+	Origin           types.String `tfsdk:"origin"`
+	AllowCredentials types.Bool   `tfsdk:"allow_credentials"`
+	Project          types.String `tfsdk:"project"`
+	Token            types.String `tfsdk:"token"`
+}
+
+func (r *CORSOriginResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 has no auto_created attribute. It is left null and set
+		// by the next read.
+		0: {
+			PriorSchema: &tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"id":                {Type: types.StringType, Computed: true},
+					"origin":            {Type: types.StringType, Required: true},
+					"allow_credentials": {Type: types.BoolType, Optional: true, Computed: true},
+					"project":           {Type: types.StringType, Required: true},
+					"token":             {Type: types.StringType, Optional: true, Sensitive: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior corsOriginResourceModelV0
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data := CORSOriginResourceModel{
+					Id:               prior.Id,
+					Origin:           prior.Origin,
+					AllowCredentials: prior.AllowCredentials,
+					AutoCreated:      types.Bool{Null: true},
+					Project:          prior.Project,
+					Token:            prior.Token,
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
+}
+
+// corsOriginAutoCreated reports whether the origin is the one Sanity creates
+// for the studio host of the project.
+func corsOriginAutoCreated(origin string, project *sanity.Project) bool {
+	studioHost := normalizeStudioHost(project.StudioHost)
+	return studioHost != "" && origin == studioURL(studioHost)
+}
+
+// parseCORSEntryId parses the ID of a CORS entry as it is stored in state. An
+// ID that is not a number means that the state is corrupted.
+func parseCORSEntryId(id string) (int64, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	rawId, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		diags.AddAttributeError(
+			path.Root("id"),
+			"Invalid CORS Entry ID",
+			fmt.Sprintf("The CORS entry ID %q is not a number, which indicates that the state is corrupted. Remove the resource from state and import it again.", id),
+		)
+	}
+
+	return rawId, diags
+}
@@ -0,0 +1,451 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// testCheckMockCORSOrigins checks the origins of the CORS entries of the
+// project in the mock and whether they allow credentials.
+func testCheckMockCORSOrigins(m *mockSanity, projectId string, want map[string]bool) resource.TestCheckFunc {
+	return testCheckMock(func() error {
+		got := map[string]bool{}
+		m.withProject(projectId, func(p *mockProject) {
+			for _, e := range p.cors {
+				got[e.Origin] = e.AllowCredentials
+			}
+		})
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			return fmt.Errorf("expected CORS origins %v, got %v", want, got)
+		}
+		return nil
+	})
+}
+
+func TestCORSOriginResource(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "create, replace and delete",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project = "p1"
+  origin  = "https://example.com"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_cors_origin.test", "id", "1"),
+							resource.TestCheckResourceAttr("sanity_cors_origin.test", "allow_credentials", "true"),
+							resource.TestCheckResourceAttr("sanity_cors_origin.test", "auto_created", "false"),
+							testCheckMockCORSOrigins(m, "p1", map[string]bool{"https://example.com": true}),
+						),
+					},
+					{
+						// a CORS entry cannot be updated, so it is replaced
+						Config: m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project           = "p1"
+  origin            = "https://example.com"
+  allow_credentials = false
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_cors_origin.test", "id", "2"),
+							resource.TestCheckResourceAttr("sanity_cors_origin.test", "allow_credentials", "false"),
+							testCheckMockCORSOrigins(m, "p1", map[string]bool{"https://example.com": false}),
+						),
+					},
+				}
+			},
+			checkDestroy: func(m *mockSanity) error {
+				return testCheckMockCORSOrigins(m, "p1", map[string]bool{})(nil)
+			},
+		},
+		{
+			name: "the studio host entry is adopted",
+			setup: func(m *mockSanity) {
+				p := m.addProject("p1", "Test")
+				p.project.StudioHost = "test"
+				p.cors = []sanity.CORSEntry{{Id: 42, Origin: "https://test.sanity.studio", AllowCredentials: true, ProjectId: "p1"}}
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project = "p1"
+  origin  = "https://test.sanity.studio"
+}
+`),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_cors_origin.test", "id", "42"),
+						resource.TestCheckResourceAttr("sanity_cors_origin.test", "auto_created", "true"),
+						testCheckRequestCount(m, "POST", "/projects/p1/cors", 0),
+					),
+				}}
+			},
+		},
+		{
+			name: "another existing entry is a conflict",
+			setup: func(m *mockSanity) {
+				p := m.addProject("p1", "Test")
+				p.project.StudioHost = "test"
+				p.cors = []sanity.CORSEntry{{Id: 42, Origin: "https://example.com", AllowCredentials: true, ProjectId: "p1"}}
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project = "p1"
+  origin  = "https://example.com"
+}
+`),
+						ExpectError: regexp.MustCompile(`The origin https://example.com already exists in project p1 \(id\s+42\)\.\s+Import\s+it`),
+					},
+					{
+						Config: m.providerConfig(""),
+						Check:  testCheckRequestCount(m, "POST", "/projects/p1/cors", 0),
+					},
+				}
+			},
+		},
+		{
+			name: "externally deleted",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project = "p1"
+  origin  = "https://example.com"
+}
+`)
+				return []resource.TestStep{
+					{
+						Config: config,
+					},
+					{
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.cors = nil
+							})
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+					},
+					{
+						Config: config,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_cors_origin.test", "id", "2"),
+							testCheckMockCORSOrigins(m, "p1", map[string]bool{"https://example.com": true}),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "moved",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_cors_origin" "old" {
+  project = "p1"
+  origin  = "https://example.com"
+}
+`),
+					},
+					{
+						// the ID does not depend on the address, so the move
+						// does not change anything
+						Config: m.providerConfig(`
+resource "sanity_cors_origin" "new" {
+  project = "p1"
+  origin  = "https://example.com"
+}
+
+moved {
+  from = sanity_cors_origin.old
+  to   = sanity_cors_origin.new
+}
+`),
+						PlanOnly: true,
+					},
+				}
+			},
+		},
+		{
+			name: "import",
+			setup: func(m *mockSanity) {
+				p := m.addProject("p1", "Test")
+				p.cors = []sanity.CORSEntry{{Id: 42, Origin: "https://example.com", AllowCredentials: false, ProjectId: "p1"}}
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project           = "p1"
+  origin            = "https://example.com"
+  allow_credentials = false
+}
+`)
+				return []resource.TestStep{
+					{
+						Config:             config,
+						ResourceName:       "sanity_cors_origin.test",
+						ImportState:        true,
+						ImportStateId:      "p1/https://example.com",
+						ImportStatePersist: true,
+					},
+					{
+						Config:   config,
+						PlanOnly: true,
+						Check:    resource.TestCheckResourceAttr("sanity_cors_origin.test", "id", "42"),
+					},
+				}
+			},
+		},
+		{
+			name: "import from a later page",
+			setup: func(m *mockSanity) {
+				m.pageSize = 1
+				p := m.addProject("p1", "Test")
+				p.cors = []sanity.CORSEntry{
+					{Id: 40, Origin: "https://a.example.com", AllowCredentials: true, ProjectId: "p1"},
+					{Id: 41, Origin: "https://b.example.com", AllowCredentials: true, ProjectId: "p1"},
+					{Id: 42, Origin: "https://example.com", AllowCredentials: false, ProjectId: "p1"},
+				}
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := m.providerConfig(`
+resource "sanity_cors_origin" "test" {
+  project           = "p1"
+  origin            = "https://example.com"
+  allow_credentials = false
+}
+`)
+				return []resource.TestStep{
+					{
+						Config:             config,
+						ResourceName:       "sanity_cors_origin.test",
+						ImportState:        true,
+						ImportStateId:      "p1/https://example.com",
+						ImportStatePersist: true,
+					},
+					{
+						Config:   config,
+						PlanOnly: true,
+						Check:    resource.TestCheckResourceAttr("sanity_cors_origin.test", "id", "42"),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestCORSOriginResource_defaultAllowCredentials(t *testing.T) {
+	config := `
+resource "sanity_cors_origin" "test" {
+  project = "p1"
+  origin  = "https://example.com"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "the provider default applies",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfigWith(`default_cors_allow_credentials = false`, config),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_cors_origin.test", "allow_credentials", "false"),
+						testCheckMockCORSOrigins(m, "p1", map[string]bool{"https://example.com": false}),
+					),
+				}}
+			},
+		},
+		{
+			name: "the resource overrides the provider default",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfigWith(`default_cors_allow_credentials = false`, `
+resource "sanity_cors_origin" "test" {
+  project           = "p1"
+  origin            = "https://example.com"
+  allow_credentials = true
+}
+`),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_cors_origin.test", "allow_credentials", "true"),
+						testCheckMockCORSOrigins(m, "p1", map[string]bool{"https://example.com": true}),
+					),
+				}}
+			},
+		},
+		{
+			name: "changing the provider default keeps existing origins",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config),
+						Check:  resource.TestCheckResourceAttr("sanity_cors_origin.test", "allow_credentials", "true"),
+					},
+					{
+						Config:   m.providerConfigWith(`default_cors_allow_credentials = false`, config),
+						PlanOnly: true,
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestCORSOriginResource_delayedEntry(t *testing.T) {
+	m := newMockSanity(t)
+	p := m.addProject("p1", "Test")
+	p.cors = []sanity.CORSEntry{{Id: 42, Origin: "https://example.com", AllowCredentials: false, ProjectId: "p1"}}
+	m.emptyLists("/projects/p1/cors", 2)
+
+	rt := newResourceTest(t, m, NewCORSOriginResource())
+	state, diags := rt.read(rt.stateWith(map[string]attr.Value{
+		"project": types.String{Value: "p1"},
+		"id":      types.String{Value: "42"},
+		"origin":  types.String{Value: "https://example.com"},
+	}))
+	requireNoDiagnostics(t, diags)
+
+	data := stateModel[CORSOriginResourceModel](t, state)
+	if data.Id.Value != "42" || data.AllowCredentials.Value {
+		t.Fatalf("expected the entry to be found on a retry, got %+v", data)
+	}
+	if n := len(m.requestsTo("GET", "/projects/p1/cors")); n != 3 {
+		t.Fatalf("expected 3 list requests, got %d", n)
+	}
+}
+
+func TestCORSOriginResource_upgradeFromV0(t *testing.T) {
+	m := newMockSanity(t)
+	p := m.addProject("p1", "Test")
+	p.project.StudioHost = "test"
+	p.cors = []sanity.CORSEntry{{Id: 42, Origin: "https://test.sanity.studio", AllowCredentials: true, ProjectId: "p1"}}
+
+	rt := newResourceTest(t, m, NewCORSOriginResource())
+	state, diags := rt.upgradeState(0, `{
+  "id": "42",
+  "origin": "https://test.sanity.studio",
+  "allow_credentials": true,
+  "project": "p1",
+  "token": null
+}`)
+	requireNoDiagnostics(t, diags)
+
+	data := stateModel[CORSOriginResourceModel](t, state)
+	if data.Id.Value != "42" || data.Origin.Value != "https://test.sanity.studio" || !data.AllowCredentials.Value || data.Project.Value != "p1" || !data.AutoCreated.Null {
+		t.Fatalf("unexpected upgraded state: %+v", data)
+	}
+
+	state, diags = rt.read(state)
+	requireNoDiagnostics(t, diags)
+	if data := stateModel[CORSOriginResourceModel](t, state); data.Id.Value != "42" || !data.AutoCreated.Value {
+		t.Fatalf("expected the read to keep the ID and set auto_created, got %+v", data)
+	}
+}
+
+func TestCORSOriginResource_malformedId(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+
+	rt := newResourceTest(t, m, NewCORSOriginResource())
+	state := rt.stateWith(map[string]attr.Value{
+		"project": types.String{Value: "p1"},
+		"id":      types.String{Value: "12abc"},
+		"origin":  types.String{Value: "https://example.com"},
+	})
+
+	_, diags := rt.read(state)
+	requireErrorDiagnostic(t, diags, "Invalid CORS Entry ID")
+	requireErrorDiagnostic(t, rt.delete(state), "Invalid CORS Entry ID")
+	if n := len(m.requestsTo("DELETE", "/projects/p1/cors/.*")); n != 0 {
+		t.Fatalf("expected no delete request, got %d", n)
+	}
+}
+
+func TestParseCORSEntryId(t *testing.T) {
+	cases := []struct {
+		id      string
+		want    int64
+		wantErr bool
+	}{
+		{id: "42", want: 42},
+		{id: "0", want: 0},
+		{id: "9223372036854775807", want: 9223372036854775807},
+		{id: "", wantErr: true},
+		{id: "12abc", wantErr: true},
+		{id: "abc", wantErr: true},
+		{id: "4.2", wantErr: true},
+		{id: "9223372036854775808", wantErr: true},
+	}
+
+	for _, tc := range cases {
+		got, diags := parseCORSEntryId(tc.id)
+		if diags.HasError() != tc.wantErr {
+			t.Errorf("parseCORSEntryId(%q): expected an error: %t, got %v", tc.id, tc.wantErr, diags)
+			continue
+		}
+		if tc.wantErr {
+			requireErrorDiagnostic(t, diags, "which indicates that the state is corrupted")
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseCORSEntryId(%q) = %d, want %d", tc.id, got, tc.want)
+		}
+	}
+}
+
+func TestCORSOriginAutoCreated(t *testing.T) {
+	cases := []struct {
+		name       string
+		origin     string
+		studioHost string
+		want       bool
+	}{
+		{name: "studio host", origin: "https://test.sanity.studio", studioHost: "test", want: true},
+		{name: "studio host reported as a URL", origin: "https://test.sanity.studio", studioHost: "https://test.sanity.studio/", want: true},
+		{name: "another studio host", origin: "https://other.sanity.studio", studioHost: "test"},
+		{name: "plain http", origin: "http://test.sanity.studio", studioHost: "test"},
+		{name: "trailing slash", origin: "https://test.sanity.studio/", studioHost: "test"},
+		{name: "no studio host", origin: "https://.sanity.studio"},
+		{name: "local studio", origin: "http://localhost:3333", studioHost: "test"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := corsOriginAutoCreated(tc.origin, &sanity.Project{StudioHost: tc.studioHost}); got != tc.want {
+				t.Fatalf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
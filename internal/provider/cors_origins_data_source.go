@@ -0,0 +1,151 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &CORSOriginsDataSource{}
+
+func NewCORSOriginsDataSource() datasource.DataSource {
+	return &CORSOriginsDataSource{}
+}
+
+// CORSOriginsDataSource defines the data source implementation.
+type CORSOriginsDataSource struct {
+	client *Client
+}
+
+// CORSOriginsDataSourceModel describes the data source data model.
+type CORSOriginsDataSourceModel struct {
+	Project          types.String                       `tfsdk:"project"`
+	AllowCredentials types.Bool                         `tfsdk:"allow_credentials"`
+	Origins          []CORSOriginsDataSourceOriginModel `tfsdk:"origins"`
+}
+
+// CORSOriginsDataSourceOriginModel describes a single CORS origin in the data
+// source data model.
+type CORSOriginsDataSourceOriginModel struct {
+	Id               types.String `tfsdk:"id"`
+	Origin           types.String `tfsdk:"origin"`
+	AllowCredentials types.Bool   `tfsdk:"allow_credentials"`
+	AutoCreated      types.Bool   `tfsdk:"auto_created"`
+}
+
+func (d *CORSOriginsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cors_origins"
+}
+
+func (d *CORSOriginsDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets the CORS origins configured for a Sanity project, optionally only those that allow credentials.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the CORS origins belong to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"allow_credentials": {
+				MarkdownDescription: "When set, only the CORS origins whose `allow_credentials` matches this value are returned. Omit it to return all the CORS origins of the project.",
+				Type:                types.BoolType,
+				Optional:            true,
+			},
+			"origins": {
+				MarkdownDescription: "The CORS origins configured for the project that match the `allow_credentials` filter.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						MarkdownDescription: "The unique ID for the CORS origin. This is the same ID used by the `sanity_cors_origin` resource.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"origin": {
+						MarkdownDescription: "The origin that traffic is allowed from.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"allow_credentials": {
+						MarkdownDescription: "Indicates whether the origin is allowed to send credentials.",
+						Type:                types.BoolType,
+						Computed:            true,
+					},
+					"auto_created": {
+						MarkdownDescription: "Indicates whether the origin is the one Sanity creates for the `studio_host` of the project, which the studio hosted by Sanity needs. It is derived from the origin and the current studio host.",
+						Type:                types.BoolType,
+						Computed:            true,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *CORSOriginsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *CORSOriginsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CORSOriginsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	project, err := d.client.Projects.Get(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	entries, err := d.client.ListCORSEntries(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Origins = make([]CORSOriginsDataSourceOriginModel, 0, len(entries))
+	for _, entry := range entries {
+		if !data.AllowCredentials.Null && entry.AllowCredentials != data.AllowCredentials.Value {
+			continue
+		}
+		data.Origins = append(data.Origins, CORSOriginsDataSourceOriginModel{
+			Id:               types.String{Value: fmt.Sprintf("%d", entry.Id)},
+			Origin:           types.String{Value: entry.Origin},
+			AllowCredentials: types.Bool{Value: entry.AllowCredentials},
+			AutoCreated:      types.Bool{Value: corsOriginAutoCreated(entry.Origin, project)},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// TestCORSOriginsDataSource calls the data source directly, as it has no `id`
+// attribute that the test harness of terraform-plugin-sdk requires.
+func TestCORSOriginsDataSource(t *testing.T) {
+	ctx := context.Background()
+
+	m := newMockSanity(t)
+	p := m.addProject("p1", "Test")
+	p.project.StudioHost = "test"
+	p.cors = []sanity.CORSEntry{
+		{Id: 101, Origin: "https://test.sanity.studio", AllowCredentials: true, ProjectId: "p1"},
+		{Id: 102, Origin: "https://www.example.com", AllowCredentials: false, ProjectId: "p1"},
+	}
+
+	d := NewCORSOriginsDataSource()
+	schema, diags := d.GetSchema(ctx)
+	requireNoDiagnostics(t, diags)
+
+	var configureResp datasource.ConfigureResponse
+	d.(datasource.DataSourceWithConfigure).Configure(ctx, datasource.ConfigureRequest{ProviderData: m.client(t)}, &configureResp)
+	requireNoDiagnostics(t, configureResp.Diagnostics)
+
+	config := tfsdk.Config{Schema: schema}
+	state := tfsdk.State{Schema: schema}
+	requireNoDiagnostics(t, state.Set(ctx, &CORSOriginsDataSourceModel{
+		Project:          types.String{Value: "p1"},
+		AllowCredentials: types.Bool{Null: true},
+	}))
+	config.Raw = state.Raw
+
+	resp := datasource.ReadResponse{State: state}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+	requireNoDiagnostics(t, resp.Diagnostics)
+
+	data := stateModel[CORSOriginsDataSourceModel](t, resp.State)
+	if len(data.Origins) != 2 {
+		t.Fatalf("expected 2 origins, got %+v", data.Origins)
+	}
+	for _, o := range data.Origins {
+		want := o.Origin.Value == "https://test.sanity.studio"
+		if o.AutoCreated.Value != want {
+			t.Errorf("expected auto_created of %s to be %t, got %t", o.Origin.Value, want, o.AutoCreated.Value)
+		}
+	}
+}
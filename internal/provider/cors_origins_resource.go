@@ -0,0 +1,400 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tessellator/go-sanity/sanity"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_plan_modifier"
+)
+
+var _ resource.Resource = &CORSOriginsResource{}
+var _ resource.ResourceWithImportState = &CORSOriginsResource{}
+var _ resource.ResourceWithModifyPlan = &CORSOriginsResource{}
+
+func NewCORSOriginsResource() resource.Resource {
+	return &CORSOriginsResource{}
+}
+
+type CORSOriginsResource struct {
+	client *Client
+}
+
+type CORSOriginsResourceModel struct {
+	Project        types.String                     `tfsdk:"project"`
+	Origins        []CORSOriginsResourceOriginModel `tfsdk:"origin"`
+	DetectExternal types.Bool                       `tfsdk:"detect_external"`
+	Token          types.String                     `tfsdk:"token"`
+}
+
+type CORSOriginsResourceOriginModel struct {
+	Origin           types.String `tfsdk:"origin"`
+	AllowCredentials types.Bool   `tfsdk:"allow_credentials"`
+}
+
+// corsOriginKey identifies a CORS entry by its settings. The Sanity API does
+// not support updating a CORS entry, so an entry either matches exactly or is
+// replaced.
+type corsOriginKey struct {
+	origin           string
+	allowCredentials bool
+}
+
+// corsOriginKeys returns the keys of the origins in the model.
+func corsOriginKeys(data *CORSOriginsResourceModel) map[corsOriginKey]bool {
+	keys := make(map[corsOriginKey]bool, len(data.Origins))
+	for _, o := range data.Origins {
+		keys[corsOriginKey{o.Origin.Value, o.AllowCredentials.Value}] = true
+	}
+	return keys
+}
+
+func (r *CORSOriginsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cors_origins"
+}
+
+func (r *CORSOriginsResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Manages the complete set of CORS origins of a Sanity project. Entries that are not listed are deleted, including the entries Sanity creates for a new project and for the `studio_host` of a project; list the `studio_url` of the project to keep access to a studio hosted by Sanity. Do not combine this resource with `sanity_cors_origin` resources for the same project, unless `detect_external` is set.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage these CORS entries instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
+			"project": {
+				Required:            true,
+				MarkdownDescription: "The ID of the project that the CORS origins belong to.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"detect_external": {
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				MarkdownDescription: "Indicates whether entries that are not listed are reported instead of deleted. When set, the resource only manages the listed entries, and the plan warns about every other entry of the project, such as an origin added in the Sanity management console or by a `sanity_cors_origin` resource. Defaults to `false`.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.Bool{Value: false}),
+				},
+			},
+		},
+
+		Blocks: map[string]tfsdk.Block{
+			"origin": {
+				MarkdownDescription: "A CORS origin that is allowed to connect to the project. Entries that already exist are left alone; changing an entry deletes and recreates it.",
+				NestingMode:         tfsdk.BlockNestingModeSet,
+				Attributes: map[string]tfsdk.Attribute{
+					"origin": {
+						Required:            true,
+						MarkdownDescription: "The origin you want to allow traffic from, stating explicitly the protocol, host name and port. Wildcards (`*`) are allowed. Use the following format: `protocol://host:port`.",
+						Type:                types.StringType,
+					},
+					"allow_credentials": {
+						Required:            true,
+						MarkdownDescription: "Indicates whether the origin is allowed to send credentials (e.g. a session cookie or an authorization token).",
+						Type:                types.BoolType,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (r *CORSOriginsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+// ModifyPlan warns about the entries of the project that are neither listed
+// nor in state when detect_external is set. The check is skipped when the provider is not
+// configured yet or the values are unknown.
+func (r *CORSOriginsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var plan *CORSOriginsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() || !plan.DetectExternal.Value || plan.Project.Unknown {
+		return
+	}
+
+	for _, o := range plan.Origins {
+		if o.Origin.Unknown || o.AllowCredentials.Unknown {
+			return
+		}
+	}
+
+	ctx = contextWithToken(ctx, plan.Token)
+
+	entries, err := r.client.ListCORSEntries(ctx, plan.Project.Value)
+	if isNotFound(err) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	// the entries that are in state but no longer planned are deleted, so they
+	// are managed as well
+	managed := corsOriginKeys(plan)
+	if !req.State.Raw.IsNull() {
+		var state *CORSOriginsResourceModel
+
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for key := range corsOriginKeys(state) {
+			managed[key] = true
+		}
+	}
+
+	for _, e := range entries {
+		if managed[corsOriginKey{e.Origin, e.AllowCredentials}] {
+			continue
+		}
+		resp.Diagnostics.AddWarning(
+			"Unmanaged CORS Origin",
+			fmt.Sprintf("Project %s has CORS entry %d for origin %s (allow_credentials = %t) that is not listed in this resource. It was probably added outside of this configuration; list it to manage it, or delete it in Sanity.", plan.Project.Value, e.Id, e.Origin, e.AllowCredentials),
+		)
+	}
+}
+
+// reconcile creates the planned origins that are missing and deletes the
+// entries that are not planned. Matching entries are left alone. With
+// detect_external, only the unplanned entries in the prior state are deleted;
+// prior is nil on create.
+func (r *CORSOriginsResource) reconcile(ctx context.Context, data *CORSOriginsResourceModel, prior *CORSOriginsResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	want := corsOriginKeys(data)
+
+	var managed map[corsOriginKey]bool
+	if data.DetectExternal.Value {
+		managed = make(map[corsOriginKey]bool)
+		if prior != nil {
+			managed = corsOriginKeys(prior)
+		}
+	}
+
+	entries, err := r.client.ListCORSEntries(ctx, data.Project.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	// deletes go first so that a changed entry does not conflict with itself
+	for _, e := range entries {
+		key := corsOriginKey{e.Origin, e.AllowCredentials}
+		if want[key] {
+			delete(want, key)
+			continue
+		}
+		if managed != nil && !managed[key] {
+			continue
+		}
+		_, err := r.client.Projects.DeleteCORSEntry(ctx, data.Project.Value, e.Id)
+		if err != nil && !isNotFound(err) {
+			diags.Append(clientErrorDiagnosticf(err, "entry %d could not be deleted", e.Id))
+			return diags
+		}
+	}
+
+	for key := range want {
+		_, err := r.client.Projects.CreateCORSEntry(ctx, data.Project.Value, &sanity.CreateCORSEntryRequest{
+			Origin:           key.origin,
+			AllowCredentials: sanity.NewBool(key.allowCredentials),
+		})
+		if err != nil {
+			diags.Append(clientErrorDiagnosticf(err, "origin %s could not be created", key.origin))
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func (r *CORSOriginsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *CORSOriginsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity cors entries", map[string]interface{}{"project": data.Project.Value})
+
+	resp.Diagnostics.Append(r.reconcile(ctx, data, nil)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CORSOriginsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *CORSOriginsResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity cors entries", map[string]interface{}{"project": data.Project.Value})
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	entries, err := r.client.ListCORSEntries(ctx, data.Project.Value)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	// with detect_external, the entries that are not in state are reported by
+	// ModifyPlan instead of showing up as drift
+	var managed map[corsOriginKey]bool
+	if data.DetectExternal.Value {
+		managed = corsOriginKeys(data)
+	}
+
+	data.Origins = make([]CORSOriginsResourceOriginModel, 0, len(entries))
+	for _, e := range entries {
+		if managed != nil && !managed[corsOriginKey{e.Origin, e.AllowCredentials}] {
+			continue
+		}
+		data.Origins = append(data.Origins, CORSOriginsResourceOriginModel{
+			Origin:           types.String{Value: e.Origin},
+			AllowCredentials: types.Bool{Value: e.AllowCredentials},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CORSOriginsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data, prior *CORSOriginsResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity cors entries", map[string]interface{}{"project": data.Project.Value})
+
+	resp.Diagnostics.Append(r.reconcile(ctx, data, prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *CORSOriginsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *CORSOriginsResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity cors entries", map[string]interface{}{"project": data.Project.Value})
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	// only the entries in state are deleted, so entries created after the last
+	// refresh are left alone
+	managed := corsOriginKeys(data)
+
+	entries, err := r.client.ListCORSEntries(ctx, data.Project.Value)
+	if isNotFound(err) {
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	for _, e := range entries {
+		if !managed[corsOriginKey{e.Origin, e.AllowCredentials}] {
+			continue
+		}
+		_, err := r.client.Projects.DeleteCORSEntry(ctx, data.Project.Value, e.Id)
+		if err != nil && !isNotFound(err) {
+			resp.Diagnostics.Append(clientErrorDiagnosticf(err, "entry %d could not be deleted", e.Id))
+			return
+		}
+	}
+}
+
+func (r *CORSOriginsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("detect_external"), false)...)
+}
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// corsOriginsPlan returns the plan for the origins, given as origin and
+// whether it allows credentials.
+func corsOriginsPlan(project string, origins map[string]bool) CORSOriginsResourceModel {
+	data := CORSOriginsResourceModel{
+		Project:        types.String{Value: project},
+		DetectExternal: types.Bool{Value: false},
+		Token:          types.String{Null: true},
+	}
+	for origin, allowCredentials := range origins {
+		data.Origins = append(data.Origins, CORSOriginsResourceOriginModel{
+			Origin:           types.String{Value: origin},
+			AllowCredentials: types.Bool{Value: allowCredentials},
+		})
+	}
+	return data
+}
+
+// checkMockCORSOrigins checks the origins of the CORS entries of the project
+// in the mock and whether they allow credentials.
+func checkMockCORSOrigins(t *testing.T, m *mockSanity, projectId string, want map[string]bool) {
+	t.Helper()
+
+	if err := testCheckMockCORSOrigins(m, projectId, want)(nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkCORSRequests checks how many CORS entries were created and deleted.
+func checkCORSRequests(t *testing.T, m *mockSanity, creates int, deletes int) {
+	t.Helper()
+
+	gotCreates := len(m.requestsTo("POST", "/projects/p1/cors"))
+	gotDeletes := len(m.requestsTo("DELETE", "/projects/p1/cors/.*"))
+	if gotCreates != creates || gotDeletes != deletes {
+		t.Fatalf("expected %d creates and %d deletes, got %d and %d", creates, deletes, gotCreates, gotDeletes)
+	}
+}
+
+func TestCORSOriginsResource(t *testing.T) {
+	cases := []struct {
+		name string
+		test func(t *testing.T, m *mockSanity, rt *resourceTest)
+	}{
+		{
+			name: "create replaces the entries of the project",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				_, diags := rt.create(corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true, "http://localhost:3333": true}))
+				requireNoDiagnostics(t, diags)
+
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"https://a.example.com": true, "http://localhost:3333": true})
+				checkCORSRequests(t, m, 1, 1)
+			},
+		},
+		{
+			name: "add, remove, change and no-op",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				m.withProject("p1", func(p *mockProject) {
+					p.cors = nil
+				})
+				state, diags := rt.create(corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true, "https://b.example.com": true}))
+				requireNoDiagnostics(t, diags)
+				checkCORSRequests(t, m, 2, 0)
+
+				// add
+				state, diags = rt.update(state, corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true, "https://b.example.com": true, "https://c.example.com": false}))
+				requireNoDiagnostics(t, diags)
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"https://a.example.com": true, "https://b.example.com": true, "https://c.example.com": false})
+				checkCORSRequests(t, m, 3, 0)
+
+				// remove
+				state, diags = rt.update(state, corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true, "https://c.example.com": false}))
+				requireNoDiagnostics(t, diags)
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"https://a.example.com": true, "https://c.example.com": false})
+				checkCORSRequests(t, m, 3, 1)
+
+				// change, which replaces the entry
+				state, diags = rt.update(state, corsOriginsPlan("p1", map[string]bool{"https://a.example.com": false, "https://c.example.com": false}))
+				requireNoDiagnostics(t, diags)
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"https://a.example.com": false, "https://c.example.com": false})
+				checkCORSRequests(t, m, 4, 2)
+
+				// no-op
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				_, diags = rt.update(state, corsOriginsPlan("p1", map[string]bool{"https://a.example.com": false, "https://c.example.com": false}))
+				requireNoDiagnostics(t, diags)
+				checkCORSRequests(t, m, 4, 2)
+
+				requireNoDiagnostics(t, rt.delete(state))
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{})
+			},
+		},
+		{
+			name: "read reports entries added outside of Terraform",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				m.withProject("p1", func(p *mockProject) {
+					p.cors = nil
+				})
+				state, diags := rt.create(corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true}))
+				requireNoDiagnostics(t, diags)
+
+				m.withProject("p1", func(p *mockProject) {
+					p.cors = append(p.cors, sanity.CORSEntry{Id: 99, Origin: "https://external.example.com", ProjectId: "p1"})
+				})
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[CORSOriginsResourceModel](t, state); len(data.Origins) != 2 {
+					t.Fatalf("expected the external entry to show up as drift, got %v", data.Origins)
+				}
+			},
+		},
+		{
+			name: "detect_external leaves other entries alone",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				plan := corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true})
+				plan.DetectExternal = types.Bool{Value: true}
+
+				resp := rt.modifyPlan(nil, plan)
+				requireNoDiagnostics(t, resp.Diagnostics)
+				if n := len(resp.Diagnostics.Warnings()); n != 2 {
+					t.Fatalf("expected a warning for each unlisted entry, got %v", resp.Diagnostics)
+				}
+
+				state, diags := rt.create(plan)
+				requireNoDiagnostics(t, diags)
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"https://a.example.com": true, "http://localhost:3333": true, "https://old.example.com": false})
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[CORSOriginsResourceModel](t, state); len(data.Origins) != 1 {
+					t.Fatalf("expected only the listed entry in state, got %v", data.Origins)
+				}
+
+				requireNoDiagnostics(t, rt.delete(state))
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"http://localhost:3333": true, "https://old.example.com": false})
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			m.addProject("p1", "Test")
+			m.withProject("p1", func(p *mockProject) {
+				p.cors = []sanity.CORSEntry{
+					{Id: 101, Origin: "http://localhost:3333", AllowCredentials: true, ProjectId: "p1"},
+					{Id: 102, Origin: "https://old.example.com", AllowCredentials: false, ProjectId: "p1"},
+				}
+			})
+
+			tc.test(t, m, newResourceTest(t, m, NewCORSOriginsResource()))
+		})
+	}
+}
+
+func TestCORSOriginsResource_detectExternal(t *testing.T) {
+	// detectExternalPlan returns a plan for the origins with detect_external
+	detectExternalPlan := func(origins map[string]bool) CORSOriginsResourceModel {
+		plan := corsOriginsPlan("p1", origins)
+		plan.DetectExternal = types.Bool{Value: true}
+		return plan
+	}
+
+	cases := []struct {
+		name string
+		test func(t *testing.T, m *mockSanity, rt *resourceTest)
+	}{
+		{
+			name: "no warning when every entry is listed",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				resp := rt.modifyPlan(nil, detectExternalPlan(map[string]bool{"https://a.example.com": true}))
+				requireNoDiagnostics(t, resp.Diagnostics)
+				if len(resp.Diagnostics) != 0 {
+					t.Fatalf("expected no warnings, got %v", resp.Diagnostics)
+				}
+			},
+		},
+		{
+			name: "an entry added outside of Terraform is reported and kept",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				plan := detectExternalPlan(map[string]bool{"https://a.example.com": true, "https://b.example.com": false})
+				state, diags := rt.create(plan)
+				requireNoDiagnostics(t, diags)
+
+				m.withProject("p1", func(p *mockProject) {
+					p.cors = append(p.cors, sanity.CORSEntry{Id: 103, Origin: "https://external.example.com", ProjectId: "p1"})
+				})
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[CORSOriginsResourceModel](t, state); len(data.Origins) != 2 {
+					t.Fatalf("expected the external entry to be left out of state, got %v", data.Origins)
+				}
+
+				plan = detectExternalPlan(map[string]bool{"https://a.example.com": true})
+				resp := rt.modifyPlan(&state, plan)
+				requireNoDiagnostics(t, resp.Diagnostics)
+				warnings := resp.Diagnostics.Warnings()
+				if len(warnings) != 1 || !strings.Contains(warnings[0].Detail(), "CORS entry 103 for origin https://external.example.com") {
+					t.Fatalf("expected a warning for the external entry, got %v", resp.Diagnostics)
+				}
+
+				// removing a listed origin only deletes that entry
+				_, diags = rt.update(state, plan)
+				requireNoDiagnostics(t, diags)
+				checkMockCORSOrigins(t, m, "p1", map[string]bool{"https://a.example.com": true, "https://external.example.com": false})
+			},
+		},
+		{
+			name: "entries are not listed without detect_external",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				m.withProject("p1", func(p *mockProject) {
+					p.cors = append(p.cors, sanity.CORSEntry{Id: 103, Origin: "https://external.example.com", ProjectId: "p1"})
+				})
+
+				resp := rt.modifyPlan(nil, corsOriginsPlan("p1", map[string]bool{"https://a.example.com": true}))
+				requireNoDiagnostics(t, resp.Diagnostics)
+				if len(resp.Diagnostics) != 0 || len(m.requestsTo("GET", "/projects/p1/cors")) != 0 {
+					t.Fatalf("expected no warnings and no requests, got %v", resp.Diagnostics)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			m.addProject("p1", "Test")
+			m.withProject("p1", func(p *mockProject) {
+				p.cors = nil
+			})
+
+			tc.test(t, m, newResourceTest(t, m, NewCORSOriginsResource()))
+		})
+	}
+}
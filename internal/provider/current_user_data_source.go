@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &CurrentUserDataSource{}
+
+func NewCurrentUserDataSource() datasource.DataSource {
+	return &CurrentUserDataSource{}
+}
+
+// CurrentUserDataSource defines the data source implementation.
+type CurrentUserDataSource struct {
+	client *Client
+}
+
+// CurrentUserDataSourceModel describes the data source data model.
+type CurrentUserDataSourceModel struct {
+	Id    types.String `tfsdk:"id"`
+	Name  types.String `tfsdk:"name"`
+	Email types.String `tfsdk:"email"`
+	Roles types.List   `tfsdk:"roles"`
+}
+
+func (d *CurrentUserDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_current_user"
+}
+
+func (d *CurrentUserDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets the Sanity user that the provider token authenticates as. For a project token, this is the robot user that Sanity created for the token.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "The unique user ID generated by Sanity. This is the ID used as the `member_id` of a `sanity_project_member`.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"name": {
+				MarkdownDescription: "The name of the user.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"email": {
+				MarkdownDescription: "The email address of the user. Robot users have no email address.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"roles": {
+				MarkdownDescription: "The names of the roles assigned to the user.",
+				Type:                types.ListType{ElemType: types.StringType},
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (d *CurrentUserDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *CurrentUserDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CurrentUserDataSourceModel
+
+	user, err := d.client.GetCurrentUser(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Id = types.String{Value: user.Id}
+	data.Name = types.String{Value: user.Name}
+	data.Email = types.String{Value: user.Email}
+	data.Roles = types.List{ElemType: types.StringType, Elems: []attr.Value{}}
+	for _, role := range user.Roles {
+		data.Roles.Elems = append(data.Roles.Elems, types.String{Value: role.Name})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_validator"
+)
+
+var _ resource.Resource = &DatasetACLResource{}
+var _ resource.ResourceWithImportState = &DatasetACLResource{}
+
+var datasetPermissionRegexp = regexp.MustCompile(`^(read|write)$`)
+
+func NewDatasetACLResource() resource.Resource {
+	return &DatasetACLResource{}
+}
+
+type DatasetACLResource struct {
+	client *Client
+}
+
+type DatasetACLResourceModel struct {
+	Project types.String                   `tfsdk:"project"`
+	Dataset types.String                   `tfsdk:"dataset"`
+	Grants  []DatasetACLResourceGrantModel `tfsdk:"grant"`
+	Token   types.String                   `tfsdk:"token"`
+}
+
+type DatasetACLResourceGrantModel struct {
+	Role       types.String `tfsdk:"role"`
+	Permission types.String `tfsdk:"permission"`
+}
+
+func (r *DatasetACLResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dataset_acl"
+}
+
+func (r *DatasetACLResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Manages which roles can read or write a Sanity dataset. This is typically used to give access to a `private` dataset. The resource manages all of the grants on the dataset, so any grant not listed is removed.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage the grants of this dataset instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
+			"project": {
+				Required:            true,
+				Type:                types.StringType,
+				MarkdownDescription: "The ID of the project that the dataset belongs to.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"dataset": {
+				Required:            true,
+				Type:                types.StringType,
+				MarkdownDescription: "The name of the dataset.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+		},
+
+		Blocks: map[string]tfsdk.Block{
+			"grant": {
+				MarkdownDescription: "Gives a role access to the dataset.",
+				NestingMode:         tfsdk.BlockNestingModeList,
+				Attributes: map[string]tfsdk.Attribute{
+					"role": {
+						Required:            true,
+						MarkdownDescription: "The name of the role, such as `viewer` or a custom role.",
+						Type:                types.StringType,
+					},
+					"permission": {
+						Required:            true,
+						MarkdownDescription: "The access given to the role. Valid options are `read` and `write`.",
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							attribute_validator.StringMatches(datasetPermissionRegexp, "The permission must be read or write"),
+						},
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (r *DatasetACLResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (m *DatasetACLResourceModel) toGrants() []DatasetGrant {
+	grants := make([]DatasetGrant, 0, len(m.Grants))
+	for _, g := range m.Grants {
+		grants = append(grants, DatasetGrant{
+			Role:       g.Role.Value,
+			Permission: g.Permission.Value,
+		})
+	}
+	return grants
+}
+
+func (m *DatasetACLResourceModel) fromGrants(grants []DatasetGrant) {
+	m.Grants = make([]DatasetACLResourceGrantModel, 0, len(grants))
+	for _, g := range grants {
+		m.Grants = append(m.Grants, DatasetACLResourceGrantModel{
+			Role:       types.String{Value: g.Role},
+			Permission: types.String{Value: g.Permission},
+		})
+	}
+}
+
+func (r *DatasetACLResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *DatasetACLResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity dataset acl", map[string]interface{}{"project": data.Project.Value, "dataset": data.Dataset.Value})
+
+	grants, err := r.client.SetDatasetGrants(ctx, data.Project.Value, data.Dataset.Value, data.toGrants())
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.fromGrants(grants)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DatasetACLResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *DatasetACLResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity dataset acl", map[string]interface{}{"project": data.Project.Value, "dataset": data.Dataset.Value})
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+	if data.Dataset.Null {
+		resp.Diagnostics.AddError("Dataset is null", "Dataset is null")
+		return
+	}
+
+	grants, err := r.client.ListDatasetGrants(ctx, data.Project.Value, data.Dataset.Value)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.fromGrants(grants)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DatasetACLResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *DatasetACLResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity dataset acl", map[string]interface{}{"project": data.Project.Value, "dataset": data.Dataset.Value})
+
+	grants, err := r.client.SetDatasetGrants(ctx, data.Project.Value, data.Dataset.Value, data.toGrants())
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.fromGrants(grants)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *DatasetACLResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *DatasetACLResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity dataset acl", map[string]interface{}{"project": data.Project.Value, "dataset": data.Dataset.Value})
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+	if data.Dataset.Null {
+		resp.Diagnostics.AddError("Dataset is null", "Dataset is null")
+		return
+	}
+
+	_, err := r.client.SetDatasetGrants(ctx, data.Project.Value, data.Dataset.Value, nil)
+
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "grants on dataset %s could not be removed", data.Dataset.Value))
+		return
+	}
+}
+
+func (r *DatasetACLResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectId, datasetName, _ := strings.Cut(req.ID, "/")
+	if projectId == "" || datasetName == "" {
+		resp.Diagnostics.AddError("Import Error", "The format for importing a dataset ACL is project-id/dataset-name")
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), resource.ImportStateRequest{ID: projectId}, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("dataset"), resource.ImportStateRequest{ID: datasetName}, resp)
+}
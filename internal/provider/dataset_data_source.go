@@ -0,0 +1,134 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &DatasetDataSource{}
+
+func NewDatasetDataSource() datasource.DataSource {
+	return &DatasetDataSource{}
+}
+
+// DatasetDataSource defines the data source implementation.
+type DatasetDataSource struct {
+	client *Client
+}
+
+// DatasetDataSourceModel describes the data source data model.
+type DatasetDataSourceModel struct {
+	Project types.String `tfsdk:"project"`
+	Name    types.String `tfsdk:"name"`
+	AclMode types.String `tfsdk:"acl_mode"`
+	Tags    types.Set    `tfsdk:"tags"`
+}
+
+func (d *DatasetDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dataset"
+}
+
+func (d *DatasetDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets a dataset in a Sanity project.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the dataset belongs to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"name": {
+				MarkdownDescription: "The name of the dataset.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"acl_mode": {
+				MarkdownDescription: "The ACL mode for the data. Either `public` or `private`.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"tags": {
+				MarkdownDescription: "The names of the tags assigned to the dataset.",
+				Type:                types.SetType{ElemType: types.StringType},
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (d *DatasetDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *DatasetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DatasetDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	datasets, err := d.client.ListDatasets(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var dataset sanity.Dataset
+	found := false
+
+	for _, ds := range datasets {
+		if ds.Name == data.Name.Value {
+			dataset = ds
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		resp.Diagnostics.AddError("dataset not found", fmt.Sprintf("No dataset named %q was found in project %s", data.Name.Value, data.Project.Value))
+		return
+	}
+
+	tags, err := d.client.Projects.ListsDatasetTags(ctx, data.Project.Value, dataset.Name)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.AclMode = types.String{Value: dataset.AclMode}
+	data.Tags = datasetTagsSet(tags)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -3,31 +3,52 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/tessellator/go-sanity/sanity"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_plan_modifier"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_validator"
 )
 
 var _ resource.Resource = &DatasetResource{}
 var _ resource.ResourceWithImportState = &DatasetResource{}
+var _ resource.ResourceWithUpgradeState = &DatasetResource{}
+var _ resource.ResourceWithValidateConfig = &DatasetResource{}
+var _ resource.ResourceWithModifyPlan = &DatasetResource{}
+
+var datasetNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// datasetCopyPollInterval is how often the copy job is checked while a dataset
+// is being copied. It is a variable so that tests can shorten it.
+var datasetCopyPollInterval = 5 * time.Second
 
 func NewDatasetResource() resource.Resource {
 	return &DatasetResource{}
 }
 
 type DatasetResource struct {
-	client *sanity.Client
+	client *Client
 }
 
 type DatasetResourceModel struct {
-	Project types.String `tfsdk:"project"`
-	Name    types.String `tfsdk:"name"`
-	AclMode types.String `tfsdk:"acl_mode"`
+	Project     types.String `tfsdk:"project"`
+	Name        types.String `tfsdk:"name"`
+	AclMode     types.String `tfsdk:"acl_mode"`
+	CopyFrom    types.String `tfsdk:"copy_from"`
+	Tags        types.Set    `tfsdk:"tags"`
+	Adopt       types.Bool   `tfsdk:"adopt_existing"`
+	AllowRename types.Bool   `tfsdk:"allow_rename"`
+	Description types.String `tfsdk:"description"`
+	Token       types.String `tfsdk:"token"`
 }
 
 func (r *DatasetResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -36,9 +57,16 @@ func (r *DatasetResource) Metadata(ctx context.Context, req resource.MetadataReq
 
 func (r *DatasetResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
-		MarkdownDescription: "Provides a dataset to a Sanity project. A dataset is like a database for your content, and you manage its contents with a studio and query it with GROQ or GraphQL.",
+		MarkdownDescription: "Provides a dataset to a Sanity project. A dataset is like a database for your content, and you manage its contents with a studio and query it with GROQ or GraphQL.\n\nThe `acl_mode` and `tags` of a dataset are updated in place. Changing the `project`, `name`, or `copy_from` forces a new dataset to be created, unless `allow_rename` is set, in which case a changed `name` renames the dataset.",
+		Version:             1,
 
 		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this dataset instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
 			"project": {
 				Required:            true,
 				Type:                types.StringType,
@@ -50,36 +78,119 @@ func (r *DatasetResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Dia
 			"name": {
 				Required:            true,
 				Type:                types.StringType,
-				MarkdownDescription: "The name of the dataset.",
-				PlanModifiers: tfsdk.AttributePlanModifiers{
-					resource.RequiresReplace(),
+				MarkdownDescription: "The name of the dataset. The name may only contain lowercase letters, numbers, underscores, and dashes, must start with a letter or number, and can be at most 64 characters long. Changing the name forces a new, empty dataset to be created, unless `allow_rename` is set.",
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.StringMatches(datasetNameRegexp, "The dataset name may only contain lowercase letters, numbers, underscores, and dashes, must start with a letter or number, and can be at most 64 characters long"),
 				},
 			},
 			"acl_mode": {
 				Optional:            true,
 				Computed:            true,
 				Type:                types.StringType,
-				MarkdownDescription: "The ACL mode for the data. Valid options are `public` and `private`.",
+				MarkdownDescription: "The ACL mode for the data. Valid options are `public` and `private`. Defaults to `public`. Changing the ACL mode updates the dataset in place.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.String{Value: "public"}),
+				},
+			},
+			"copy_from": {
+				Optional:            true,
+				Type:                types.StringType,
+				MarkdownDescription: "The name of a dataset in the same project to copy documents and assets from when the dataset is created. Copying a dataset is only available on business and enterprise plans. Changing this value forces a new dataset to be created.",
 				PlanModifiers: tfsdk.AttributePlanModifiers{
 					resource.RequiresReplace(),
 				},
 			},
+			"description": {
+				Optional:            true,
+				Type:                types.StringType,
+				MarkdownDescription: "A description of the purpose of the dataset. The Sanity API has no description for datasets, so it is stored in the metadata of the project under the key `datasetDescription.<name>`, where it also shows up in the `all_metadata` of a `sanity_project`. Changing the description updates it in place, and removing it clears the metadata entry.",
+			},
+			"adopt_existing": {
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				MarkdownDescription: "Indicates whether a dataset with the same name that already exists in the project is adopted when the resource is created, instead of failing. The `acl_mode` and `tags` of an adopted dataset are updated to match the configuration. This is useful to bring datasets created outside of Terraform under management. It does not apply to a dataset created with `copy_from`. Defaults to `false`.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.Bool{Value: false}),
+				},
+			},
+			"allow_rename": {
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				MarkdownDescription: "Indicates whether a changed `name` renames the dataset instead of replacing it with an empty one. Sanity cannot rename a dataset, so the dataset is copied to the new name, the copy job is waited on until it finishes, and the old dataset is deleted. Copying takes a while for a large dataset, counts against the plan limits of the project, and is only available on business and enterprise plans. Documents written to the old dataset while it is being copied are lost. Defaults to `false`.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.Bool{Value: false}),
+				},
+			},
+			"tags": {
+				Optional:            true,
+				Computed:            true,
+				Type:                types.SetType{ElemType: types.StringType},
+				MarkdownDescription: "The names of the tags assigned to the dataset. The tags must already exist in the project, for example as `sanity_tag` resources. When not set, the tags assigned to the dataset are not managed.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
 		},
 	}, nil
 }
 
+func (r *DatasetResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data DatasetResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.CopyFrom.Null || data.CopyFrom.Unknown || data.Name.Unknown {
+		return
+	}
+
+	if data.CopyFrom.Value == data.Name.Value {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("copy_from"),
+			"Invalid Attribute Value",
+			"A dataset cannot be copied from itself",
+		)
+	}
+}
+
+// ModifyPlan replaces the dataset when its name changes, unless allow_rename is
+// set, in which case Update renames it.
+func (r *DatasetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to replace on create or destroy
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var name, priorName types.String
+	var allowRename types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("allow_rename"), &allowRename)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &priorName)...)
+
+	if resp.Diagnostics.HasError() || name.Equal(priorName) || allowRename.Value {
+		return
+	}
+
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("name"))
+}
+
 func (r *DatasetResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*sanity.Client)
+	client, ok := req.ProviderData.(*Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -89,6 +200,11 @@ func (r *DatasetResource) Configure(ctx context.Context, req resource.ConfigureR
 }
 
 func (r *DatasetResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *DatasetResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -97,18 +213,296 @@ func (r *DatasetResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	_, err := r.client.Projects.CreateDataset(ctx, data.Project.Value, &sanity.CreateDatasetRequest{
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity dataset", map[string]interface{}{"project": data.Project.Value, "name": data.Name.Value})
+
+	if !data.CopyFrom.Null {
+		r.copy(ctx, data, resp)
+		return
+	}
+
+	dataset, err := r.client.Projects.CreateDataset(ctx, data.Project.Value, &sanity.CreateDatasetRequest{
 		Name:    data.Name.Value,
 		AclMode: data.AclMode.Value,
 	})
+	if isConflict(err) && data.Adopt.Value {
+		r.adopt(ctx, data, resp)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
+	data.AclMode = types.String{Value: dataset.AclMode}
+
+	resp.Diagnostics.Append(r.applyDescription(ctx, data, types.String{Null: true})...)
+	resp.Diagnostics.Append(r.applyTags(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// adopt takes over a dataset that already exists and brings its ACL mode and
+// tags in line with the plan.
+func (r *DatasetResource) adopt(ctx context.Context, data *DatasetResourceModel, resp *resource.CreateResponse) {
+	datasets, err := r.client.ListDatasets(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var existing *sanity.Dataset
+	for i := range datasets {
+		if datasets[i].Name == data.Name.Value {
+			existing = &datasets[i]
+			break
+		}
+	}
+	if existing == nil {
+		resp.Diagnostics.AddError("Dataset Not Found", fmt.Sprintf("Sanity reported that dataset %s already exists in project %s, but it is not in the list of datasets.", data.Name.Value, data.Project.Value))
+		return
+	}
+
+	tflog.Info(ctx, "adopted an existing sanity dataset", map[string]interface{}{"project": data.Project.Value, "name": existing.Name})
+
+	aclMode := data.AclMode
+	data.AclMode = types.String{Value: existing.AclMode}
+	if aclMode.Value != existing.AclMode {
+		dataset, err := r.client.UpdateDataset(ctx, data.Project.Value, data.Name.Value, &UpdateDatasetRequest{
+			AclMode: aclMode.Value,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
+		data.AclMode = types.String{Value: dataset.AclMode}
+	}
+
+	resp.Diagnostics.Append(r.applyDescription(ctx, data, types.String{Null: true})...)
+	resp.Diagnostics.Append(r.applyTags(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// copy creates the dataset as a copy of the `copy_from` dataset and waits for
+// the copy job to finish.
+func (r *DatasetResource) copy(ctx context.Context, data *DatasetResourceModel, resp *resource.CreateResponse) {
+	copyResp, err := r.client.Projects.CopyDataset(ctx, data.Project.Value, &sanity.CopyDatasetRequest{
+		SourceDataset: data.CopyFrom.Value,
+		TargetDataset: data.Name.Value,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	aclMode := data.AclMode
+
+	// the target dataset exists as soon as the copy starts, so it is tracked
+	// even if the copy does not finish
+	data.AclMode = types.String{Value: copyResp.AclMode}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.waitForCopy(ctx, copyResp.JobId, data.CopyFrom.Value, data.Name.Value)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// a copy takes the ACL mode of its source, so apply the planned one
+	if aclMode.Value != copyResp.AclMode {
+		dataset, err := r.client.UpdateDataset(ctx, data.Project.Value, data.Name.Value, &UpdateDatasetRequest{
+			AclMode: aclMode.Value,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
+		data.AclMode = types.String{Value: dataset.AclMode}
+	}
+
+	resp.Diagnostics.Append(r.applyDescription(ctx, data, types.String{Null: true})...)
+	resp.Diagnostics.Append(r.applyTags(ctx, data)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
+// waitForCopy polls the copy job of a dataset until it finishes.
+func (r *DatasetResource) waitForCopy(ctx context.Context, jobId string, source string, target string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for {
+		job, err := r.client.GetJob(ctx, jobId)
+		if err != nil {
+			diags.Append(clientErrorDiagnosticf(err, "could not check on the copy of dataset %s", source))
+			return diags
+		}
+
+		tflog.Info(ctx, "copying sanity dataset", map[string]interface{}{
+			"source":   source,
+			"target":   target,
+			"job":      job.Id,
+			"state":    job.State,
+			"progress": job.Progress,
+		})
+
+		if job.State == JobStateCompleted {
+			return diags
+		}
+		if job.State == JobStateFailed {
+			diags.AddError("Dataset Copy Failed", fmt.Sprintf("The copy of dataset %s into %s failed (job %s)", source, target, job.Id))
+			return diags
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Dataset Copy Interrupted", fmt.Sprintf("Stopped waiting for the copy of dataset %s into %s (job %s): %s", source, target, job.Id, ctx.Err()))
+			return diags
+		case <-time.After(datasetCopyPollInterval):
+		}
+	}
+}
+
+// rename copies the dataset from its prior name to the planned one and deletes
+// the old dataset once the copy is done. The description is moved along by
+// the caller; the one stored under the old name is cleared here.
+func (r *DatasetResource) rename(ctx context.Context, data *DatasetResourceModel, oldName string, description types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	copyResp, err := r.client.Projects.CopyDataset(ctx, data.Project.Value, &sanity.CopyDatasetRequest{
+		SourceDataset: oldName,
+		TargetDataset: data.Name.Value,
+	})
+	if err != nil {
+		diags.Append(clientErrorDiagnosticf(err, "dataset %s could not be copied to %s", oldName, data.Name.Value))
+		return diags
+	}
+
+	diags.Append(r.waitForCopy(ctx, copyResp.JobId, oldName, data.Name.Value)...)
+	if diags.HasError() {
+		diags.AddError("Dataset Rename Incomplete", fmt.Sprintf("Dataset %s was not renamed and is left alone. Dataset %s may have been created by the copy; delete it before trying again.", oldName, data.Name.Value))
+		return diags
+	}
+
+	tflog.Info(ctx, "renamed sanity dataset", map[string]interface{}{"project": data.Project.Value, "from": oldName, "to": data.Name.Value})
+
+	// the content is safe in the new dataset, so failing to clean up the old
+	// one only leaves it behind
+	_, err = r.client.Projects.DeleteDataset(ctx, data.Project.Value, oldName)
+	if err != nil && !isNotFound(err) {
+		diags.AddWarning("Old Dataset Not Deleted", fmt.Sprintf("Dataset %s was copied to %s, but could not be deleted, got error: %s", oldName, data.Name.Value, err))
+	}
+
+	if !description.Null {
+		_, err = r.client.UpdateProjectMetadata(ctx, data.Project.Value, map[string]string{
+			datasetDescriptionKey(oldName): "",
+		})
+		if err != nil {
+			diags.AddWarning("Description Not Cleared", fmt.Sprintf("Dataset %s was renamed to %s, but its old description could not be removed from the metadata of project %s, got error: %s", oldName, data.Name.Value, data.Project.Value, err))
+		}
+	}
+
+	return diags
+}
+
+// datasetDescriptionKey is the project metadata key that holds the
+// description of a dataset.
+func datasetDescriptionKey(name string) string {
+	return "datasetDescription." + name
+}
+
+// applyDescription stores the planned description of the dataset in the
+// project metadata when it differs from the prior one. A removed description
+// is cleared.
+func (r *DatasetResource) applyDescription(ctx context.Context, data *DatasetResourceModel, prior types.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if data.Description.Unknown {
+		data.Description = types.String{Null: true}
+	}
+	if data.Description.Null == prior.Null && data.Description.Value == prior.Value {
+		return diags
+	}
+
+	_, err := r.client.UpdateProjectMetadata(ctx, data.Project.Value, map[string]string{
+		datasetDescriptionKey(data.Name.Value): data.Description.Value,
+	})
+	if err != nil {
+		diags.Append(clientErrorDiagnosticf(err, "the description of dataset %s could not be saved", data.Name.Value))
+	}
+
+	return diags
+}
+
+// datasetDescriptionValue returns the description of the dataset from the
+// project metadata, or null if it has none.
+func datasetDescriptionValue(project *sanity.Project, name string) types.String {
+	description := project.Metadata[datasetDescriptionKey(name)]
+	if description == "" {
+		return types.String{Null: true}
+	}
+	return types.String{Value: description}
+}
+
+// applyTags assigns and unassigns tags so that the dataset has the planned
+// tags. When the tags are not configured, the current tags are read instead.
+func (r *DatasetResource) applyTags(ctx context.Context, data *DatasetResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	current, err := r.client.Projects.ListsDatasetTags(ctx, data.Project.Value, data.Name.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	if data.Tags.Null || data.Tags.Unknown {
+		data.Tags = datasetTagsSet(current)
+		return diags
+	}
+
+	var planned []string
+	diags.Append(data.Tags.ElementsAs(ctx, &planned, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	want := make(map[string]bool, len(planned))
+	for _, name := range planned {
+		want[name] = true
+	}
+
+	for _, tag := range current {
+		if want[tag.Name] {
+			delete(want, tag.Name)
+			continue
+		}
+		_, err := r.client.Projects.UnassignDatasetTag(ctx, data.Project.Value, data.Name.Value, tag.Name)
+		if err != nil {
+			diags.Append(clientErrorDiagnosticf(err, "tag %s could not be removed from dataset %s", tag.Name, data.Name.Value))
+			return diags
+		}
+	}
+
+	for name := range want {
+		err := r.client.Projects.AssignDatasetTag(ctx, data.Project.Value, data.Name.Value, name)
+		if err != nil {
+			diags.Append(clientErrorDiagnosticf(err, "tag %s could not be assigned to dataset %s", name, data.Name.Value))
+			return diags
+		}
+	}
+
+	return diags
+}
+
+func datasetTagsSet(tags []sanity.DatasetTag) types.Set {
+	set := types.Set{ElemType: types.StringType, Elems: []attr.Value{}}
+	for _, tag := range tags {
+		set.Elems = append(set.Elems, types.String{Value: tag.Name})
+	}
+	return set
+}
+
 func (r *DatasetResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var data *DatasetResourceModel
 
@@ -119,40 +513,126 @@ func (r *DatasetResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity dataset", map[string]interface{}{"project": data.Project.Value, "name": data.Name.Value})
+
 	projectId := data.Project.Value
 
-	datasets, err := r.client.Projects.ListDatasets(ctx, projectId)
+	var dataset sanity.Dataset
+	found, err := findWithRetry(ctx, func() (bool, error) {
+		datasets, err := r.client.ListDatasets(ctx, projectId)
+		if err != nil {
+			return false, err
+		}
+		for _, d := range datasets {
+			if d.Name == data.Name.Value {
+				dataset = d
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
-	var dataset sanity.Dataset
-	found := false
+	// a missing dataset is not an error: it was deleted outside of Terraform
+	// and will be recreated on the next apply
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-	for _, d := range datasets {
-		if d.Name == data.Name.Value {
-			dataset = d
-			found = true
-			break
-		}
+	data.AclMode = types.String{Value: dataset.AclMode}
+
+	tags, err := r.client.Projects.ListsDatasetTags(ctx, projectId, data.Name.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
 	}
 
-	if !found {
-		resp.Diagnostics.AddError("dataset not found", "dataset not found")
+	data.Tags = datasetTagsSet(tags)
+
+	project, err := r.client.Projects.Get(ctx, projectId)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
-	data.AclMode = types.String{Value: dataset.AclMode}
+	data.Description = datasetDescriptionValue(project, data.Name.Value)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DatasetResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("Provider Error", "Update is not supported on dataset")
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *DatasetResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity dataset", map[string]interface{}{"project": data.Project.Value, "name": data.Name.Value})
+
+	var name, aclMode, description types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("name"), &name)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("acl_mode"), &aclMode)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("description"), &description)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the name only changes here when allow_rename is set; otherwise ModifyPlan
+	// forces a replacement
+	if name.Value != data.Name.Value {
+		resp.Diagnostics.Append(r.rename(ctx, data, name.Value, description)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		// nothing is stored under the new name yet
+		description = types.String{Null: true}
+	}
+
+	// a renamed dataset is a copy that keeps the ACL mode of the old one, so
+	// the ACL mode in state applies to it as well
+	if data.AclMode.Value != aclMode.Value {
+		dataset, err := r.client.UpdateDataset(ctx, data.Project.Value, data.Name.Value, &UpdateDatasetRequest{
+			AclMode: data.AclMode.Value,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
+
+		data.AclMode = types.String{Value: dataset.AclMode}
+	}
+
+	resp.Diagnostics.Append(r.applyDescription(ctx, data, description)...)
+	resp.Diagnostics.Append(r.applyTags(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *DatasetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *DatasetResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -161,6 +641,9 @@ func (r *DatasetResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity dataset", map[string]interface{}{"project": data.Project.Value, "name": data.Name.Value})
+
 	if data.Name.Null {
 		resp.Diagnostics.AddError("Name is null", "Name is null")
 		return
@@ -172,22 +655,105 @@ func (r *DatasetResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	_, err := r.client.Projects.DeleteDataset(ctx, data.Project.Value, data.Name.Value)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("dataset %s could not be deleted, got error: %s", data.Name.Value, err))
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "dataset %s could not be deleted", data.Name.Value))
 		return
 	}
+
+	// the dataset is gone, so a description that cannot be cleared is only
+	// left behind in the project metadata
+	if !data.Description.Null {
+		_, err = r.client.UpdateProjectMetadata(ctx, data.Project.Value, map[string]string{
+			datasetDescriptionKey(data.Name.Value): "",
+		})
+		if err != nil && !isNotFound(err) {
+			resp.Diagnostics.AddWarning("Description Not Cleared", fmt.Sprintf("Dataset %s was deleted, but its description could not be removed from the metadata of project %s, got error: %s", data.Name.Value, data.Project.Value, err))
+		}
+	}
 }
 
 func (r *DatasetResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	parts := strings.Split(req.ID, "/")
-	if len(parts) != 2 {
+	projectId, name, _ := strings.Cut(req.ID, "/")
+	if projectId == "" || name == "" || strings.Contains(name, "/") {
 		resp.Diagnostics.AddError("Input Error", "The import identifier for a dataset should be in the form project-id/dataset-name")
 		return
 	}
 
-	reqProject := resource.ImportStateRequest{ID: parts[0]}
-	reqName := resource.ImportStateRequest{ID: parts[1]}
+	// the ACL mode is set from the dataset so that the imported resource
+	// matches it even before it is refreshed
+	datasets, err := r.client.ListDatasets(ctx, projectId)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var dataset *sanity.Dataset
+	for _, d := range datasets {
+		if d.Name == name {
+			found := d
+			dataset = &found
+			break
+		}
+	}
+	if dataset == nil {
+		resp.Diagnostics.AddError("Import Error", fmt.Sprintf("Dataset %s was not found in project %s", name, projectId))
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), resource.ImportStateRequest{ID: projectId}, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), resource.ImportStateRequest{ID: name}, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("acl_mode"), dataset.AclMode)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adopt_existing"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("allow_rename"), false)...)
+}
 
-	resource.ImportStatePassthroughID(ctx, path.Root("project"), reqProject, resp)
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), reqName, resp)
+// datasetResourceModelV0 is the model of version 0 of the schema.
+type datasetResourceModelV0 struct {
+	Project  types.String `tfsdk:"project"`
+	Name     types.String `tfsdk:"name"`
+	AclMode  types.String `tfsdk:"acl_mode"`
+	CopyFrom types.String `tfsdk:"copy_from"`
+	Tags     types.Set    `tfsdk:"tags"`
+	Token    types.String `tfsdk:"token"`
+}
+
+func (r *DatasetResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 has no adopt_existing, allow_rename, or description.
+		0: {
+			PriorSchema: &tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"token":     {Type: types.StringType, Optional: true, Sensitive: true},
+					"project":   {Type: types.StringType, Required: true},
+					"name":      {Type: types.StringType, Required: true},
+					"acl_mode":  {Type: types.StringType, Optional: true, Computed: true},
+					"copy_from": {Type: types.StringType, Optional: true},
+					"tags":      {Type: types.SetType{ElemType: types.StringType}, Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior datasetResourceModelV0
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data := DatasetResourceModel{
+					Project:     prior.Project,
+					Name:        prior.Name,
+					AclMode:     prior.AclMode,
+					CopyFrom:    prior.CopyFrom,
+					Tags:        prior.Tags,
+					Adopt:       types.Bool{Value: false},
+					AllowRename: types.Bool{Value: false},
+					Description: types.String{Null: true},
+					Token:       prior.Token,
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
 }
@@ -0,0 +1,432 @@
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// datasetPlan returns the plan for a dataset with the defaults of the schema.
+func datasetPlan(project string, name string, aclMode string) DatasetResourceModel {
+	return DatasetResourceModel{
+		Project:     types.String{Value: project},
+		Name:        types.String{Value: name},
+		AclMode:     types.String{Value: aclMode},
+		CopyFrom:    types.String{Null: true},
+		Tags:        types.Set{ElemType: types.StringType, Null: true},
+		Adopt:       types.Bool{Value: false},
+		AllowRename: types.Bool{Value: false},
+		Description: types.String{Null: true},
+		Token:       types.String{Null: true},
+	}
+}
+
+// checkMockDatasets checks the datasets of the project in the mock.
+func checkMockDatasets(t *testing.T, m *mockSanity, projectId string, want ...sanity.Dataset) {
+	t.Helper()
+
+	var datasets []sanity.Dataset
+	m.withProject(projectId, func(p *mockProject) {
+		datasets = append(datasets, p.datasets...)
+	})
+	if fmt.Sprint(datasets) != fmt.Sprint(want) {
+		t.Fatalf("expected datasets %v, got %v", want, datasets)
+	}
+}
+
+func TestDatasetResource(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(m *mockSanity)
+		test  func(t *testing.T, m *mockSanity, rt *resourceTest)
+	}{
+		{
+			name: "create, read, update and delete",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(datasetPlan("p1", "production", "public"))
+				requireNoDiagnostics(t, diags)
+				checkMockDatasets(t, m, "p1", sanity.Dataset{Name: "production", AclMode: "public"})
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[DatasetResourceModel](t, state); data.AclMode.Value != "public" || len(data.Tags.Elems) != 0 {
+					t.Fatalf("unexpected state after read: %+v", data)
+				}
+
+				state, diags = rt.update(state, datasetPlan("p1", "production", "private"))
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[DatasetResourceModel](t, state); data.AclMode.Value != "private" {
+					t.Fatalf("expected acl_mode private, got %s", data.AclMode.Value)
+				}
+				checkMockDatasets(t, m, "p1", sanity.Dataset{Name: "production", AclMode: "private"})
+
+				requireNoDiagnostics(t, rt.delete(state))
+				checkMockDatasets(t, m, "p1")
+			},
+		},
+		{
+			name: "the ACL mode is only sent when it changed",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(datasetPlan("p1", "production", "private"))
+				requireNoDiagnostics(t, diags)
+
+				plan := datasetPlan("p1", "production", "private")
+				plan.Description = types.String{Value: "Live content"}
+				_, diags = rt.update(state, plan)
+				requireNoDiagnostics(t, diags)
+
+				if n := len(m.requestsTo("PATCH", "/projects/p1/datasets/production")); n != 0 {
+					t.Fatalf("expected no dataset update, got %d", n)
+				}
+				if d := m.project("p1").Metadata[datasetDescriptionKey("production")]; d != "Live content" {
+					t.Fatalf("expected the description to be updated, got %q", d)
+				}
+			},
+		},
+		{
+			name: "changing the name replaces the dataset",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(datasetPlan("p1", "production", "public"))
+				requireNoDiagnostics(t, diags)
+
+				resp := rt.modifyPlan(&state, datasetPlan("p1", "staging", "public"))
+				requireNoDiagnostics(t, resp.Diagnostics)
+				if !resp.RequiresReplace.Contains(path.Root("name")) {
+					t.Fatalf("expected the name to require a replacement, got %v", resp.RequiresReplace)
+				}
+			},
+		},
+		{
+			name: "create fails",
+			setup: func(m *mockSanity) {
+				m.fail("PUT", "/projects/p1/datasets/production", 400)
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				_, diags := rt.create(datasetPlan("p1", "production", "public"))
+				requireErrorDiagnostic(t, diags, "Sanity API Error: 400")
+				checkMockDatasets(t, m, "p1")
+			},
+		},
+		{
+			name: "an existing dataset is not adopted by default",
+			setup: func(m *mockSanity) {
+				m.withProject("p1", func(p *mockProject) {
+					p.datasets = []sanity.Dataset{{Name: "production", AclMode: "private"}}
+				})
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				_, diags := rt.create(datasetPlan("p1", "production", "public"))
+				requireErrorDiagnostic(t, diags, "Sanity API Error: 409")
+				checkMockDatasets(t, m, "p1", sanity.Dataset{Name: "production", AclMode: "private"})
+			},
+		},
+		{
+			name: "an existing dataset is adopted",
+			setup: func(m *mockSanity) {
+				m.withProject("p1", func(p *mockProject) {
+					p.datasets = []sanity.Dataset{{Name: "production", AclMode: "private"}}
+				})
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				plan := datasetPlan("p1", "production", "public")
+				plan.Adopt = types.Bool{Value: true}
+				state, diags := rt.create(plan)
+				requireNoDiagnostics(t, diags)
+
+				if data := stateModel[DatasetResourceModel](t, state); data.Name.Value != "production" || data.AclMode.Value != "public" {
+					t.Fatalf("unexpected state after adopting: %+v", data)
+				}
+				// the ACL mode of the adopted dataset is updated to match
+				checkMockDatasets(t, m, "p1", sanity.Dataset{Name: "production", AclMode: "public"})
+			},
+		},
+		{
+			name: "externally deleted",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(datasetPlan("p1", "production", "public"))
+				requireNoDiagnostics(t, diags)
+
+				m.withProject("p1", func(p *mockProject) {
+					p.datasets = nil
+				})
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if !state.Raw.IsNull() {
+					t.Fatalf("expected the dataset to be removed from state, got %v", state.Raw)
+				}
+			},
+		},
+		{
+			name: "a dataset missing from the list is retried",
+			setup: func(m *mockSanity) {
+				m.withProject("p1", func(p *mockProject) {
+					p.datasets = []sanity.Dataset{{Name: "production", AclMode: "private"}}
+				})
+				m.emptyLists("/projects/p1/datasets", 1)
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.read(rt.state(datasetPlan("p1", "production", "public")))
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[DatasetResourceModel](t, state); data.AclMode.Value != "private" {
+					t.Fatalf("expected the dataset to be found on the retry, got %+v", data)
+				}
+				if n := len(m.requestsTo("GET", "/projects/p1/datasets")); n != 2 {
+					t.Fatalf("expected 2 list requests, got %d", n)
+				}
+			},
+		},
+		{
+			name: "read fails",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(datasetPlan("p1", "production", "public"))
+				requireNoDiagnostics(t, diags)
+
+				m.fail("GET", "/projects/p1/datasets", 500)
+				state, diags = rt.read(state)
+				requireErrorDiagnostic(t, diags, "Sanity API Error: 500")
+				if state.Raw.IsNull() {
+					t.Fatal("expected the dataset to stay in state")
+				}
+			},
+		},
+		{
+			name: "read from a later page",
+			setup: func(m *mockSanity) {
+				m.pageSize = 1
+				m.withProject("p1", func(p *mockProject) {
+					p.datasets = []sanity.Dataset{{Name: "development", AclMode: "public"}, {Name: "production", AclMode: "private"}}
+				})
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.read(rt.state(datasetPlan("p1", "production", "public")))
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[DatasetResourceModel](t, state); data.AclMode.Value != "private" {
+					t.Fatalf("expected acl_mode private, got %+v", data)
+				}
+			},
+		},
+		{
+			name: "import",
+			setup: func(m *mockSanity) {
+				m.withProject("p1", func(p *mockProject) {
+					p.datasets = []sanity.Dataset{{Name: "production", AclMode: "private"}}
+				})
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.importState("p1/production")
+				requireNoDiagnostics(t, diags)
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				data := stateModel[DatasetResourceModel](t, state)
+				if data.Project.Value != "p1" || data.Name.Value != "production" || data.AclMode.Value != "private" {
+					t.Fatalf("unexpected imported state: %+v", data)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			m.addProject("p1", "Test")
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			tc.test(t, m, newResourceTest(t, m, NewDatasetResource()))
+		})
+	}
+}
+
+func TestDatasetResource_allowRename(t *testing.T) {
+	defer func(interval time.Duration) {
+		datasetCopyPollInterval = interval
+	}(datasetCopyPollInterval)
+	datasetCopyPollInterval = 10 * time.Millisecond
+
+	// renamePlan returns the plan for the dataset with allow_rename set
+	renamePlan := func(name string) DatasetResourceModel {
+		plan := datasetPlan("p1", name, "private")
+		plan.AllowRename = types.Bool{Value: true}
+		plan.Description = types.String{Value: "Live content"}
+		return plan
+	}
+
+	// jobState answers the first n requests for a job with the state, as the
+	// Sanity API does while the job runs
+	jobState := func(m *mockSanity, state string, n int) {
+		var served int
+		m.handle("GET", "/jobs/[^/]+", func(w http.ResponseWriter, r *http.Request) bool {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			if served >= n {
+				return false
+			}
+			served++
+			writeJSON(w, Job{State: state})
+			return true
+		})
+	}
+
+	cases := []struct {
+		name string
+		test func(t *testing.T, m *mockSanity, rt *resourceTest)
+	}{
+		{
+			name: "the name is updated in place",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(renamePlan("production"))
+				requireNoDiagnostics(t, diags)
+
+				resp := rt.modifyPlan(&state, renamePlan("live"))
+				requireNoDiagnostics(t, resp.Diagnostics)
+				if len(resp.RequiresReplace) != 0 {
+					t.Fatalf("expected no replacement, got %v", resp.RequiresReplace)
+				}
+			},
+		},
+		{
+			name: "the dataset is copied and the old one deleted",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(renamePlan("production"))
+				requireNoDiagnostics(t, diags)
+
+				jobState(m, JobStateRunning, 2)
+
+				state, diags = rt.update(state, renamePlan("live"))
+				requireNoDiagnostics(t, diags)
+
+				if data := stateModel[DatasetResourceModel](t, state); data.Name.Value != "live" || data.AclMode.Value != "private" || data.Description.Value != "Live content" {
+					t.Fatalf("unexpected state after the rename: %+v", data)
+				}
+				checkMockDatasets(t, m, "p1", sanity.Dataset{Name: "live", AclMode: "private"})
+
+				copies := m.requestsTo("PUT", "/projects/p1/datasets/production/copy")
+				if len(copies) != 1 || !strings.Contains(string(copies[0].Body), `"live"`) {
+					t.Fatalf("expected production to be copied to live, got %v", copies)
+				}
+				if n := len(m.requestsTo("GET", "/jobs/.*")); n != 3 {
+					t.Fatalf("expected the copy job to be polled until it completed, got %d requests", n)
+				}
+
+				metadata := m.project("p1").Metadata
+				if metadata[datasetDescriptionKey("production")] != "" || metadata[datasetDescriptionKey("live")] != "Live content" {
+					t.Fatalf("expected the description to move to the new name, got %v", metadata)
+				}
+			},
+		},
+		{
+			name: "a failed copy keeps the old dataset",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(renamePlan("production"))
+				requireNoDiagnostics(t, diags)
+
+				jobState(m, JobStateFailed, 1)
+
+				_, diags = rt.update(state, renamePlan("live"))
+				requireErrorDiagnostic(t, diags, "Dataset production was not renamed and is left alone")
+
+				if n := len(m.requestsTo("DELETE", "/projects/p1/datasets/production")); n != 0 {
+					t.Fatalf("expected the old dataset to be kept, got %d deletes", n)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			m.addProject("p1", "Test")
+
+			tc.test(t, m, newResourceTest(t, m, NewDatasetResource()))
+		})
+	}
+}
+
+func TestDatasetResource_upgradeFromV0(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+	m.withProject("p1", func(p *mockProject) {
+		p.datasets = []sanity.Dataset{{Name: "production", AclMode: "private"}}
+	})
+
+	rt := newResourceTest(t, m, NewDatasetResource())
+	state, diags := rt.upgradeState(0, `{
+  "project": "p1",
+  "name": "production",
+  "acl_mode": "private",
+  "copy_from": null,
+  "tags": [],
+  "token": null
+}`)
+	requireNoDiagnostics(t, diags)
+
+	want := datasetPlan("p1", "production", "private")
+	want.Tags = types.Set{ElemType: types.StringType, Elems: []attr.Value{}}
+	if data := stateModel[DatasetResourceModel](t, state); fmt.Sprint(data) != fmt.Sprint(want) {
+		t.Fatalf("expected the upgraded state %+v, got %+v", want, data)
+	}
+
+	state, diags = rt.read(state)
+	requireNoDiagnostics(t, diags)
+	if data := stateModel[DatasetResourceModel](t, state); fmt.Sprint(data) != fmt.Sprint(want) {
+		t.Fatalf("expected the read to keep the upgraded state %+v, got %+v", want, data)
+	}
+}
+
+// TestDatasetResource_nameValidation only plans, which the test harness of
+// terraform-plugin-sdk can do without an `id` attribute.
+func TestDatasetResource_nameValidation(t *testing.T) {
+	config := func(m *mockSanity, name string) string {
+		return m.providerConfig(fmt.Sprintf(`
+resource "sanity_dataset" "test" {
+  project = "p1"
+  name    = %q
+}
+`, name))
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "valid names",
+			steps: func(m *mockSanity) []resource.TestStep {
+				var steps []resource.TestStep
+				for _, name := range []string{"production", "a", "0", "my-dataset_2", "staging-", strings.Repeat("a", 64)} {
+					steps = append(steps, resource.TestStep{
+						Config:             config(m, name),
+						PlanOnly:           true,
+						ExpectNonEmptyPlan: true,
+					})
+				}
+				return steps
+			},
+		},
+		{
+			name: "invalid names",
+			steps: func(m *mockSanity) []resource.TestStep {
+				var steps []resource.TestStep
+				for _, name := range []string{"Production", "PRODUCTION", "-production", "_production", "my dataset", "my.dataset", "", strings.Repeat("a", 65)} {
+					steps = append(steps, resource.TestStep{
+						Config:      config(m, name),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(`The dataset name may only contain lowercase letters`),
+					})
+				}
+				return steps
+			},
+		},
+	})
+}
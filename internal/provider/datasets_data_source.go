@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &DatasetsDataSource{}
+
+func NewDatasetsDataSource() datasource.DataSource {
+	return &DatasetsDataSource{}
+}
+
+// DatasetsDataSource defines the data source implementation.
+type DatasetsDataSource struct {
+	client *Client
+}
+
+// DatasetsDataSourceModel describes the data source data model.
+type DatasetsDataSourceModel struct {
+	Project  types.String                     `tfsdk:"project"`
+	Datasets []DatasetsDataSourceDatasetModel `tfsdk:"datasets"`
+}
+
+// DatasetsDataSourceDatasetModel describes a single dataset in the data
+// source data model.
+type DatasetsDataSourceDatasetModel struct {
+	Name    types.String `tfsdk:"name"`
+	AclMode types.String `tfsdk:"acl_mode"`
+}
+
+func (d *DatasetsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_datasets"
+}
+
+func (d *DatasetsDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets all the datasets in a Sanity project.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the datasets belong to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"datasets": {
+				MarkdownDescription: "The datasets in the project.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"name": {
+						MarkdownDescription: "The name of the dataset.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"acl_mode": {
+						MarkdownDescription: "The ACL mode for the data. Either `public` or `private`.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *DatasetsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *DatasetsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data DatasetsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	datasets, err := d.client.ListDatasets(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	// an empty project yields an empty list rather than a null value
+	data.Datasets = make([]DatasetsDataSourceDatasetModel, 0, len(datasets))
+	for _, dataset := range datasets {
+		data.Datasets = append(data.Datasets, DatasetsDataSourceDatasetModel{
+			Name:    types.String{Value: dataset.Name},
+			AclMode: types.String{Value: dataset.AclMode},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
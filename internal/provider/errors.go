@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// APIError is returned when the Sanity API responds with an error status. It
+// keeps the status code so callers can tell, for example, a missing resource
+// apart from other failures.
+type APIError struct {
+	StatusCode int
+	Message    string
+	RequestId  string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return http.StatusText(e.StatusCode)
+	}
+	return e.Message
+}
+
+// newAPIError builds an APIError from an error response. The response body is
+// consumed but not closed.
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestId:  resp.Header.Get("X-Request-Id"),
+	}
+
+	// Most endpoints describe the error in `message`. The query API uses an
+	// `error` object with a `description` instead, while other endpoints set
+	// `error` to a short string.
+	type errorMessage struct {
+		Message string          `json:"message"`
+		Error   json.RawMessage `json:"error"`
+	}
+
+	type errorDetail struct {
+		Description string `json:"description"`
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		var msg errorMessage
+		if json.Unmarshal(body, &msg) == nil {
+			apiErr.Message = msg.Message
+
+			var detail errorDetail
+			if apiErr.Message == "" && json.Unmarshal(msg.Error, &detail) == nil {
+				apiErr.Message = detail.Description
+			}
+		}
+	}
+
+	return apiErr
+}
+
+// isNotFound reports whether the error is a 404 from the Sanity API.
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isConflict reports whether the error is a 409 from the Sanity API, which is
+// how it reports that a resource already exists.
+func isConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// clientErrorDiagnostic describes a failed API call. Errors returned by the
+// Sanity API include the status code, the request that failed, and the request
+// ID to help with debugging. A request that was cancelled, for example when
+// the user interrupts Terraform, is reported as such; other errors are
+// reported as they are.
+func clientErrorDiagnostic(err error) diag.Diagnostic {
+	if errors.Is(err, context.Canceled) {
+		return cancelledDiagnostic()
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return diag.NewErrorDiagnostic("Client Error", err.Error())
+	}
+
+	summary := fmt.Sprintf("Sanity API Error: %d %s", apiErr.StatusCode, http.StatusText(apiErr.StatusCode))
+
+	detail := apiErr.Error()
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		detail = fmt.Sprintf("%s %s failed: %s", strings.ToUpper(urlErr.Op), urlErr.URL, detail)
+	}
+	if apiErr.StatusCode == http.StatusForbidden && urlErr != nil {
+		if hint := permissionHint(urlErr.URL); hint != "" {
+			detail += "\n\n" + hint
+		}
+	}
+	if apiErr.RequestId != "" {
+		detail += fmt.Sprintf("\n\nRequest ID: %s", apiErr.RequestId)
+	}
+
+	return diag.NewErrorDiagnostic(summary, detail)
+}
+
+// clientErrorDiagnosticf is clientErrorDiagnostic with a message in front of
+// the detail, for a call whose failure needs more context than the request,
+// such as the second of two calls that make up one change.
+func clientErrorDiagnosticf(err error, format string, a ...interface{}) diag.Diagnostic {
+	d := clientErrorDiagnostic(err)
+	if errors.Is(err, context.Canceled) {
+		return d
+	}
+
+	return diag.NewErrorDiagnostic(d.Summary(), fmt.Sprintf(format, a...)+": "+d.Detail())
+}
+
+// permissionHints map the paths of the Sanity API, without their version, to
+// the permission that a token most likely lacks when a request is forbidden.
+// The first match wins.
+var permissionHints = []struct {
+	path *regexp.Regexp
+	hint string
+}{
+	{regexp.MustCompile(`^/projects/[^/]+/(members|users)(/|$)`), "Managing project members requires a token with the administrator role."},
+	{regexp.MustCompile(`^/invitations/`), "Managing project invitations requires a token with the administrator role."},
+	{regexp.MustCompile(`^/projects/[^/]+/tokens(/|$)`), "Managing project tokens requires a token with the administrator role."},
+	{regexp.MustCompile(`^/projects/[^/]+/(roles|permissions)(/|$)`), "Reading the roles of a project requires a token with the administrator role."},
+	{regexp.MustCompile(`^/projects/[^/]+/datasets/[^/]+/grants(/|$)`), "Managing dataset grants requires a token with the administrator role."},
+	{regexp.MustCompile(`^/projects/[^/]+/cors(/|$)`), "Managing CORS origins requires a token with the administrator or developer role."},
+	{regexp.MustCompile(`^/projects/[^/]+/(datasets|tags)(/|$)`), "Managing datasets and tags requires a token with the administrator or developer role."},
+	{regexp.MustCompile(`^/hooks/`), "Managing webhooks requires a token with the administrator or developer role."},
+	{regexp.MustCompile(`^/data/query/`), "Querying a dataset requires a token with read access to it, such as one with the viewer role."},
+	{regexp.MustCompile(`^/(projects|organizations)$`), "Creating projects and listing organizations requires a personal token, such as the one from sanity login, with access to the organization; project tokens cannot do this."},
+	{regexp.MustCompile(`^/projects/[^/]+$`), "Changing or deleting a project requires a token with the administrator role, and reading it requires access to the project."},
+}
+
+// apiVersionRegexp matches the version prefix of a Sanity API path.
+var apiVersionRegexp = regexp.MustCompile(`^/v\d{4}-\d{2}-\d{2}`)
+
+// permissionHint returns a hint at the permission that a forbidden request
+// needs, or an empty string if the endpoint is not known.
+func permissionHint(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	p := strings.TrimSuffix(u.Path, "/")
+	loc := apiVersionRegexp.FindStringIndex(p)
+	if loc == nil {
+		return ""
+	}
+	p = p[loc[1]:]
+
+	for _, h := range permissionHints {
+		if h.path.MatchString(p) {
+			return h.hint
+		}
+	}
+	return ""
+}
+
+// cancelledDiagnostic is reported when an operation stops because its context
+// was cancelled, which is how Terraform asks the provider to shut down.
+func cancelledDiagnostic() diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Operation Cancelled",
+		"The operation was cancelled before it finished, so some of its changes may not have been made. Run terraform apply again to complete them.",
+	)
+}
+
+// readOnlyDiagnostic is reported instead of changing anything in Sanity when
+// the provider is configured with `read_only`.
+func readOnlyDiagnostic() diag.Diagnostic {
+	return diag.NewErrorDiagnostic(
+		"Provider is read-only",
+		"The provider is configured with read_only = true, so it does not create, update or delete anything in Sanity. Reads and data sources keep working. Set read_only to false to apply this change.",
+	)
+}
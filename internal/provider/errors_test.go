@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestPermissionHint(t *testing.T) {
+	cases := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "members",
+			url:  "https://api.sanity.io/v2021-06-07/projects/p1/members/user1/roles/editor",
+			want: "Managing project members requires a token with the administrator role.",
+		},
+		{
+			name: "dataset grants before datasets",
+			url:  "https://api.sanity.io/v2021-06-07/projects/p1/datasets/production/grants",
+			want: "Managing dataset grants requires a token with the administrator role.",
+		},
+		{
+			name: "datasets",
+			url:  "https://api.sanity.io/v2021-06-07/projects/p1/datasets/production",
+			want: "Managing datasets and tags requires a token with the administrator or developer role.",
+		},
+		{
+			name: "project",
+			url:  "https://api.sanity.io/v2021-06-07/projects/p1/",
+			want: "Changing or deleting a project requires a token with the administrator role, and reading it requires access to the project.",
+		},
+		{
+			name: "query with a project host",
+			url:  "https://p1.api.sanity.io/v2021-06-07/data/query/production?query=*",
+			want: "Querying a dataset requires a token with read access to it, such as one with the viewer role.",
+		},
+		{
+			name: "unknown endpoint",
+			url:  "https://api.sanity.io/v2021-06-07/users/me",
+		},
+		{
+			name: "no API version",
+			url:  "https://api.sanity.io/projects/p1/members",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := permissionHint(tc.url); got != tc.want {
+				t.Fatalf("expected %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestClientErrorDiagnosticf(t *testing.T) {
+	err := &url.Error{
+		Op:  "Delete",
+		URL: "https://api.sanity.io/v2021-06-07/projects/p1/datasets/production",
+		Err: &APIError{StatusCode: 500, Message: "boom", RequestId: "req1"},
+	}
+
+	d := clientErrorDiagnosticf(err, "dataset %s could not be deleted", "production")
+	if want := "Sanity API Error: 500 Internal Server Error"; d.Summary() != want {
+		t.Fatalf("expected summary %q, got %q", want, d.Summary())
+	}
+	if want := "dataset production could not be deleted: DELETE https://api.sanity.io/v2021-06-07/projects/p1/datasets/production failed: boom\n\nRequest ID: req1"; d.Detail() != want {
+		t.Fatalf("expected detail %q, got %q", want, d.Detail())
+	}
+
+	d = clientErrorDiagnosticf(fmt.Errorf("wrapped: %w", context.Canceled), "dataset %s could not be deleted", "production")
+	if !d.Equal(cancelledDiagnostic()) {
+		t.Fatalf("expected the cancelled diagnostic, got %q: %q", d.Summary(), d.Detail())
+	}
+}
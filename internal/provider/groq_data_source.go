@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &GROQDataSource{}
+
+func NewGROQDataSource() datasource.DataSource {
+	return &GROQDataSource{}
+}
+
+// GROQDataSource defines the data source implementation.
+type GROQDataSource struct {
+	client *Client
+}
+
+// GROQDataSourceModel describes the data source data model.
+type GROQDataSourceModel struct {
+	Project types.String `tfsdk:"project"`
+	Dataset types.String `tfsdk:"dataset"`
+	Query   types.String `tfsdk:"query"`
+	Result  types.String `tfsdk:"result"`
+}
+
+func (d *GROQDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_groq"
+}
+
+func (d *GROQDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Runs a GROQ query against a dataset, for example to count its documents. Queries are read-only. The token of the provider must be allowed to read the dataset, which matters for private datasets.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the dataset belongs to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"dataset": {
+				MarkdownDescription: "The name of the dataset to query.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"query": {
+				MarkdownDescription: "The GROQ query, e.g. `count(*[_type == \"post\"])`.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"result": {
+				MarkdownDescription: "The result of the query, encoded as JSON. Use `jsondecode` to read it.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (d *GROQDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *GROQDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data GROQDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	result, err := d.client.Query(ctx, data.Project.Value, data.Dataset.Value, data.Query.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Result = types.String{Value: string(result)}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
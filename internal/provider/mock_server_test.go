@@ -0,0 +1,909 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// mockSanity is an in-memory stand-in for the Sanity HTTP API. It keeps just
+// enough state for the resources to be created, read, updated and deleted
+// against it, records the requests it receives, and lets a test inject
+// failures or replace the handling of a request.
+type mockSanity struct {
+	server *httptest.Server
+
+	mu sync.Mutex
+
+	// tokens are the bearer tokens that are accepted.
+	tokens map[string]bool
+
+	organizations []Organization
+	projects      map[string]*mockProject
+	jobs          map[string]*Job
+
+	// invitees are the users that are invited by email when a role is added,
+	// instead of being added to the project directly.
+	invitees map[string]bool
+
+	// pageSize splits the lists of CORS entries and datasets into pages of
+	// this size when set.
+	pageSize int
+
+	requests []mockRequest
+	failures []mockFailure
+	handlers []mockHandler
+
+	nextId int
+}
+
+type mockProject struct {
+	project     sanity.Project
+	updatedAt   time.Time
+	cors        []sanity.CORSEntry
+	datasets    []sanity.Dataset
+	grants      map[string][]DatasetGrant
+	tokens      []sanity.ProjectToken
+	invitations []Invitation
+	tags        []sanity.DatasetTag
+	datasetTags map[string][]string
+	webhooks    []Webhook
+}
+
+type mockRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+}
+
+type mockFailure struct {
+	method string
+	path   *regexp.Regexp
+	status int
+}
+
+type mockHandler struct {
+	method string
+	path   *regexp.Regexp
+
+	// handle serves the request, or reports false to leave it to the mock.
+	handle func(w http.ResponseWriter, r *http.Request) bool
+}
+
+const mockToken = "test-token"
+
+// newMockSanity starts a mock Sanity API that is stopped when the test ends.
+func newMockSanity(t *testing.T) *mockSanity {
+	t.Helper()
+
+	m := &mockSanity{
+		tokens:        map[string]bool{mockToken: true},
+		organizations: []Organization{{Id: "org1", Name: "Organization 1", Slug: "org-1"}, {Id: "org2", Name: "Organization 2", Slug: "org-2"}},
+		projects:      map[string]*mockProject{},
+		jobs:          map[string]*Job{},
+		invitees:      map[string]bool{},
+	}
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.server.Close)
+
+	return m
+}
+
+// providerConfig returns a provider block that points the provider at the mock
+// server, followed by the given configuration.
+func (m *mockSanity) providerConfig(config string) string {
+	return m.providerConfigWith("", config)
+}
+
+// providerConfigWith works like providerConfig and adds the attributes to the
+// provider block.
+func (m *mockSanity) providerConfigWith(attributes string, config string) string {
+	return fmt.Sprintf(`
+provider "sanity" {
+  token       = %q
+  api_url     = %q
+  max_retries = 0
+%s
+}
+`, mockToken, m.server.URL, attributes) + config
+}
+
+// addProject adds a project as if it had been created outside of Terraform.
+func (m *mockSanity) addProject(id string, displayName string) *mockProject {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.newProject(id, displayName, "")
+}
+
+func (m *mockSanity) newProject(id string, displayName string, organization string) *mockProject {
+	now := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	p := &mockProject{
+		project: sanity.Project{
+			Id:                  id,
+			DisplayName:         displayName,
+			OrganizationId:      organization,
+			Metadata:            map[string]string{},
+			ActivityFeedEnabled: true,
+			CreatedAt:           now,
+			Members: []sanity.Member{
+				{Id: "owner", IsCurrentUser: true, Roles: []sanity.Role{{Name: "administrator", Title: "Administrator"}}},
+			},
+		},
+		updatedAt:   now,
+		grants:      map[string][]DatasetGrant{},
+		datasetTags: map[string][]string{},
+	}
+	m.projects[id] = p
+
+	return p
+}
+
+// fail makes every request that matches the method and path fail with the
+// status. The path is a regular expression that must match the whole path
+// without its API version, such as `/projects/[^/]+/cors`.
+func (m *mockSanity) fail(method string, path string, status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failures = append(m.failures, mockFailure{method: method, path: regexp.MustCompile("^" + path + "$"), status: status})
+}
+
+// clearFailures stops the failures added with fail.
+func (m *mockSanity) clearFailures() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failures = nil
+}
+
+// handle serves the requests that match the method and path with h, which
+// leaves a request to the mock by returning false. The path is matched like in
+// fail.
+func (m *mockSanity) handle(method string, path string, h func(w http.ResponseWriter, r *http.Request) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers = append(m.handlers, mockHandler{method: method, path: regexp.MustCompile("^" + path + "$"), handle: h})
+}
+
+// emptyLists answers the first n list requests to the path with an empty list,
+// as the Sanity API can right after an item was created. The path is matched
+// like in fail.
+func (m *mockSanity) emptyLists(path string, n int) {
+	var served int
+	m.handle("GET", path, func(w http.ResponseWriter, r *http.Request) bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		if served >= n {
+			return false
+		}
+		served++
+		writeJSON(w, []struct{}{})
+		return true
+	})
+}
+
+// requestsTo returns the requests received for the method and path, which is
+// matched like in fail.
+func (m *mockSanity) requestsTo(method string, path string) []mockRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	re := regexp.MustCompile("^" + path + "$")
+	var requests []mockRequest
+	for _, r := range m.requests {
+		if r.Method == method && re.MatchString(r.Path) {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
+
+// project returns a copy of the project, or nil if it does not exist.
+func (m *mockSanity) project(id string) *sanity.Project {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.projects[id]
+	if !ok {
+		return nil
+	}
+	project := p.project
+	return &project
+}
+
+// withProject calls f with the project while holding the lock, so that a test
+// can change the project outside of Terraform.
+func (m *mockSanity) withProject(id string, f func(p *mockProject)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f(m.projects[id])
+}
+
+// deleteProject deletes the project as if it had been deleted outside of
+// Terraform.
+func (m *mockSanity) deleteProject(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.projects, id)
+}
+
+// acceptInvitation turns the pending invitation of the user into a membership.
+func (m *mockSanity) acceptInvitation(projectId string, userId string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p := m.projects[projectId]
+	for i, inv := range p.invitations {
+		if inv.UserId == userId && !inv.IsAccepted {
+			p.invitations[i].IsAccepted = true
+			p.project.Members = append(p.project.Members, sanity.Member{Id: userId, Roles: []sanity.Role{{Name: inv.Role}}})
+		}
+	}
+}
+
+var apiVersionPrefixRegexp = regexp.MustCompile(`^/v\d{4}-\d{2}-\d{2}`)
+
+func (m *mockSanity) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	path := apiVersionPrefixRegexp.ReplaceAllString(r.URL.Path, "")
+
+	m.mu.Lock()
+	m.requests = append(m.requests, mockRequest{Method: r.Method, Path: path, Header: r.Header.Clone(), Body: body})
+	handlers := append([]mockHandler(nil), m.handlers...)
+	var failure *mockFailure
+	for i := range m.failures {
+		if m.failures[i].method == r.Method && m.failures[i].path.MatchString(path) {
+			failure = &m.failures[i]
+			break
+		}
+	}
+	authorized := m.tokens[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+	m.mu.Unlock()
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	for _, h := range handlers {
+		if h.method == r.Method && h.path.MatchString(path) && h.handle(w, r) {
+			return
+		}
+	}
+
+	if !authorized {
+		writeError(w, http.StatusUnauthorized, "Unauthorized - Session not found")
+		return
+	}
+	if failure != nil {
+		writeError(w, failure.status, fmt.Sprintf("injected %d for %s %s", failure.status, r.Method, path))
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.route(w, r, strings.Split(strings.Trim(path, "/"), "/"), body)
+}
+
+func (m *mockSanity) route(w http.ResponseWriter, r *http.Request, seg []string, body []byte) {
+	switch {
+	case match(seg, "users", "me"):
+		writeJSON(w, CurrentUser{Id: "owner", Name: "Owner", Email: "owner@example.com"})
+	case match(seg, "organizations"):
+		writeJSON(w, m.organizations)
+	case match(seg, "jobs", "*"):
+		job, ok := m.jobs[seg[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "job not found")
+			return
+		}
+		writeJSON(w, job)
+	case match(seg, "projects"):
+		m.serveProjects(w, r, body)
+	case len(seg) >= 2 && seg[0] == "projects":
+		p, ok := m.projects[seg[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		m.serveProject(w, r, p, seg[2:], body)
+	case len(seg) >= 3 && seg[0] == "invitations" && seg[1] == "project":
+		p, ok := m.projects[seg[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		m.serveInvitations(w, r, p, seg[3:])
+	case len(seg) >= 3 && seg[0] == "hooks" && seg[1] == "projects":
+		p, ok := m.projects[seg[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "Project not found")
+			return
+		}
+		m.serveWebhooks(w, r, p, seg[3:], body)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (m *mockSanity) serveProjects(w http.ResponseWriter, r *http.Request, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		projects := []sanity.Project{}
+		for _, id := range m.projectIds() {
+			projects = append(projects, m.projects[id].project)
+		}
+		writeJSON(w, projects)
+	case http.MethodPost:
+		var req struct {
+			DisplayName    string `json:"displayName"`
+			OrganizationId string `json:"organizationId"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		m.nextId++
+		p := m.newProject(fmt.Sprintf("p%d", m.nextId), req.DisplayName, req.OrganizationId)
+		// Sanity allows the local studio to reach a new project
+		p.cors = append(p.cors, m.newCORSEntry(p, "http://localhost:3333", true))
+		writeJSON(w, p.project)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (m *mockSanity) projectIds() []string {
+	ids := make([]string, 0, len(m.projects))
+	for id := range m.projects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func (m *mockSanity) serveProject(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string, body []byte) {
+	switch {
+	case len(seg) == 0:
+		m.serveProjectRoot(w, r, p, body)
+	case seg[0] == "cors":
+		m.serveCORS(w, r, p, seg[1:], body)
+	case seg[0] == "datasets":
+		m.serveDatasets(w, r, p, seg[1:], body)
+	case seg[0] == "tokens":
+		m.serveTokens(w, r, p, seg[1:], body)
+	case seg[0] == "tags":
+		m.serveTags(w, r, p, seg[1:], body)
+	case seg[0] == "members":
+		m.serveMembers(w, r, p, seg[1:])
+	case match(seg, "roles"):
+		writeJSON(w, []ProjectRole{
+			{ProjectRole: sanity.ProjectRole{Name: "administrator"}, Title: "Administrator"},
+			{ProjectRole: sanity.ProjectRole{Name: "developer"}, Title: "Developer"},
+			{ProjectRole: sanity.ProjectRole{Name: "editor"}, Title: "Editor"},
+			{ProjectRole: sanity.ProjectRole{Name: "viewer"}, Title: "Viewer"},
+		})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// mockProjectResponse is a project as the API returns it, which includes when
+// it was last updated.
+type mockProjectResponse struct {
+	sanity.Project
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (m *mockSanity) serveProjectRoot(w http.ResponseWriter, r *http.Request, p *mockProject, body []byte) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, mockProjectResponse{Project: p.project, UpdatedAt: p.updatedAt})
+	case http.MethodPatch:
+		var req struct {
+			DisplayName         string            `json:"displayName"`
+			StudioHost          string            `json:"studioHost"`
+			OrganizationId      string            `json:"organizationId"`
+			Metadata            map[string]string `json:"metadata"`
+			IsDisabledByUser    *bool             `json:"isDisabledByUser"`
+			ActivityFeedEnabled *bool             `json:"activityFeedEnabled"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if req.StudioHost != "" && p.project.StudioHost != "" && req.StudioHost != p.project.StudioHost {
+			writeError(w, http.StatusBadRequest, "The studio host cannot be changed")
+			return
+		}
+		if req.DisplayName != "" {
+			p.project.DisplayName = req.DisplayName
+		}
+		if req.StudioHost != "" && p.project.StudioHost == "" {
+			p.project.StudioHost = req.StudioHost
+			p.cors = append(p.cors, m.newCORSEntry(p, studioURL(req.StudioHost), true))
+		}
+		if req.OrganizationId != "" {
+			p.project.OrganizationId = req.OrganizationId
+		}
+		for key, value := range req.Metadata {
+			if value == "" {
+				delete(p.project.Metadata, key)
+				continue
+			}
+			p.project.Metadata[key] = value
+			// the external studio host is set as `externalHost` and
+			// reported as `externalStudioHost`, and clearing one key
+			// leaves the other
+			if key == "externalHost" {
+				p.project.Metadata["externalStudioHost"] = value
+			}
+		}
+		if req.IsDisabledByUser != nil {
+			p.project.IsDisabledByUser = *req.IsDisabledByUser
+		}
+		if req.ActivityFeedEnabled != nil {
+			p.project.ActivityFeedEnabled = *req.ActivityFeedEnabled
+		}
+		p.updatedAt = p.updatedAt.Add(time.Minute)
+		writeJSON(w, p.project)
+	case http.MethodDelete:
+		delete(m.projects, p.project.Id)
+		writeJSON(w, map[string]bool{"deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (m *mockSanity) newCORSEntry(p *mockProject, origin string, allowCredentials bool) sanity.CORSEntry {
+	m.nextId++
+	return sanity.CORSEntry{
+		Id:               int64(m.nextId),
+		Origin:           origin,
+		AllowCredentials: allowCredentials,
+		ProjectId:        p.project.Id,
+	}
+}
+
+func (m *mockSanity) serveCORS(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string, body []byte) {
+	switch {
+	case len(seg) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, paginate(w, r, m.pageSize, p.cors))
+	case len(seg) == 0 && r.Method == http.MethodPost:
+		var req struct {
+			Origin           string `json:"origin"`
+			AllowCredentials *bool  `json:"allowCredentials"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		for _, e := range p.cors {
+			if e.Origin == req.Origin {
+				writeError(w, http.StatusConflict, "Origin already exists")
+				return
+			}
+		}
+		entry := m.newCORSEntry(p, req.Origin, req.AllowCredentials == nil || *req.AllowCredentials)
+		p.cors = append(p.cors, entry)
+		writeJSON(w, entry)
+	case len(seg) == 1 && r.Method == http.MethodDelete:
+		for i, e := range p.cors {
+			if strconv.FormatInt(e.Id, 10) == seg[0] {
+				p.cors = append(p.cors[:i], p.cors[i+1:]...)
+				writeJSON(w, map[string]interface{}{"id": e.Id, "deleted": true})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "CORS entry not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (p *mockProject) dataset(name string) (int, bool) {
+	for i, d := range p.datasets {
+		if d.Name == name {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// paginate returns the page of the items that the offset of the request asks
+// for, and links to the next page like the Sanity API. All items are returned
+// when the page size is 0.
+func paginate[T any](w http.ResponseWriter, r *http.Request, pageSize int, items []T) []T {
+	items = append([]T{}, items...)
+	if pageSize == 0 {
+		return items
+	}
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	end := offset + pageSize
+	if end < len(items) {
+		w.Header().Set("Link", fmt.Sprintf(`<%s?offset=%d>; rel="next"`, r.URL.Path, end))
+	} else {
+		end = len(items)
+	}
+	return items[offset:end]
+}
+
+func (m *mockSanity) serveDatasets(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string, body []byte) {
+	if len(seg) == 0 {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, paginate(w, r, m.pageSize, p.datasets))
+		return
+	}
+
+	name := seg[0]
+	i, exists := p.dataset(name)
+
+	switch {
+	case len(seg) == 1 && r.Method == http.MethodPut:
+		var req struct {
+			AclMode string `json:"aclMode"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if exists {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Dataset %q already exists", name))
+			return
+		}
+		if req.AclMode == "" {
+			req.AclMode = "public"
+		}
+		p.datasets = append(p.datasets, sanity.Dataset{Name: name, AclMode: req.AclMode})
+		writeJSON(w, map[string]string{"datasetName": name, "aclMode": req.AclMode})
+	case !exists:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Dataset %q not found", name))
+	case len(seg) == 1 && r.Method == http.MethodPatch:
+		var req struct {
+			AclMode string `json:"aclMode"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if req.AclMode != "" {
+			p.datasets[i].AclMode = req.AclMode
+		}
+		writeJSON(w, map[string]string{"datasetName": name, "aclMode": p.datasets[i].AclMode})
+	case len(seg) == 1 && r.Method == http.MethodDelete:
+		p.datasets = append(p.datasets[:i], p.datasets[i+1:]...)
+		delete(p.grants, name)
+		delete(p.datasetTags, name)
+		writeJSON(w, map[string]bool{"deleted": true})
+	case len(seg) == 2 && seg[1] == "copy" && r.Method == http.MethodPut:
+		var req struct {
+			TargetDataset string `json:"targetDataset"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if _, ok := p.dataset(req.TargetDataset); ok {
+			writeError(w, http.StatusConflict, fmt.Sprintf("Dataset %q already exists", req.TargetDataset))
+			return
+		}
+		aclMode := p.datasets[i].AclMode
+		p.datasets = append(p.datasets, sanity.Dataset{Name: req.TargetDataset, AclMode: aclMode})
+		m.nextId++
+		jobId := fmt.Sprintf("job%d", m.nextId)
+		m.jobs[jobId] = &Job{Id: jobId, State: JobStateCompleted, Progress: 100}
+		writeJSON(w, map[string]string{"datasetName": req.TargetDataset, "aclMode": aclMode, "jobId": jobId})
+	case len(seg) == 2 && seg[1] == "grants" && r.Method == http.MethodGet:
+		writeJSON(w, append([]DatasetGrant{}, p.grants[name]...))
+	case len(seg) == 2 && seg[1] == "grants" && r.Method == http.MethodPut:
+		var req struct {
+			Grants []DatasetGrant `json:"grants"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		p.grants[name] = req.Grants
+		writeJSON(w, map[string][]DatasetGrant{"grants": req.Grants})
+	case len(seg) == 2 && seg[1] == "tags" && r.Method == http.MethodGet:
+		tags := []sanity.DatasetTag{}
+		for _, tagName := range p.datasetTags[name] {
+			if j, ok := p.tag(tagName); ok {
+				tags = append(tags, p.tags[j])
+			}
+		}
+		writeJSON(w, tags)
+	case len(seg) == 3 && seg[1] == "tags" && r.Method == http.MethodPut:
+		if _, ok := p.tag(seg[2]); !ok {
+			writeError(w, http.StatusNotFound, "Tag not found")
+			return
+		}
+		p.datasetTags[name] = append(p.datasetTags[name], seg[2])
+		writeJSON(w, map[string]bool{"assigned": true})
+	case len(seg) == 3 && seg[1] == "tags" && r.Method == http.MethodDelete:
+		tags := p.datasetTags[name][:0]
+		for _, t := range p.datasetTags[name] {
+			if t != seg[2] {
+				tags = append(tags, t)
+			}
+		}
+		p.datasetTags[name] = tags
+		writeJSON(w, map[string]bool{"deleted": true})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (p *mockProject) tag(identifier string) (int, bool) {
+	for i, t := range p.tags {
+		if t.Name == identifier {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (m *mockSanity) serveTags(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string, body []byte) {
+	var req struct {
+		Name        string            `json:"name"`
+		Title       string            `json:"title"`
+		Description string            `json:"description"`
+		Metadata    map[string]string `json:"metadata"`
+	}
+
+	if len(seg) == 0 {
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if _, ok := p.tag(req.Name); ok {
+			writeError(w, http.StatusConflict, "Tag already exists")
+			return
+		}
+		tag := sanity.DatasetTag{Name: req.Name, Title: req.Title}
+		p.tags = append(p.tags, tag)
+		writeJSON(w, tag)
+		return
+	}
+
+	i, ok := p.tag(seg[0])
+	if !ok {
+		writeError(w, http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, p.tags[i])
+	case http.MethodPatch:
+		if !decode(w, body, &req) {
+			return
+		}
+		if req.Name != "" {
+			p.tags[i].Name = req.Name
+		}
+		if req.Title != "" {
+			p.tags[i].Title = req.Title
+		}
+		writeJSON(w, p.tags[i])
+	case http.MethodDelete:
+		p.tags = append(p.tags[:i], p.tags[i+1:]...)
+		writeJSON(w, map[string]bool{"deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (m *mockSanity) serveTokens(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string, body []byte) {
+	switch {
+	case len(seg) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, append([]sanity.ProjectToken{}, p.tokens...))
+	case len(seg) == 0 && r.Method == http.MethodPost:
+		var req struct {
+			Label    string `json:"label"`
+			RoleName string `json:"roleName"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		m.nextId++
+		token := sanity.ProjectToken{
+			Id:        fmt.Sprintf("tok%d", m.nextId),
+			Label:     req.Label,
+			Roles:     []sanity.Role{{Name: req.RoleName}},
+			CreatedAt: time.Date(2022, 1, 1, 0, 0, m.nextId, 0, time.UTC),
+		}
+		p.tokens = append(p.tokens, token)
+		writeJSON(w, sanity.CreateProjectTokenResponse{ProjectToken: token, Key: "sk" + token.Id})
+	case len(seg) == 1 && r.Method == http.MethodDelete:
+		for i, t := range p.tokens {
+			if t.Id == seg[0] {
+				p.tokens = append(p.tokens[:i], p.tokens[i+1:]...)
+				writeJSON(w, map[string]interface{}{"id": t.Id, "deleted": true})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Token not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (m *mockSanity) serveMembers(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string) {
+	if len(seg) == 0 {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	userId := seg[0]
+	memberIndex := -1
+	for i, member := range p.project.Members {
+		if member.Id == userId {
+			memberIndex = i
+		}
+	}
+
+	switch {
+	case len(seg) == 3 && seg[1] == "roles" && r.Method == http.MethodPut:
+		role := seg[2]
+		switch {
+		case memberIndex >= 0:
+			p.project.Members[memberIndex].Roles = append(p.project.Members[memberIndex].Roles, sanity.Role{Name: role})
+		case m.invitees[userId]:
+			m.nextId++
+			p.invitations = append(p.invitations, Invitation{Id: fmt.Sprintf("inv%d", m.nextId), Role: role, UserId: userId})
+		default:
+			p.project.Members = append(p.project.Members, sanity.Member{Id: userId, Roles: []sanity.Role{{Name: role}}})
+		}
+		writeJSON(w, map[string]bool{"created": true})
+	case memberIndex < 0:
+		writeError(w, http.StatusNotFound, "Member not found")
+	case len(seg) == 3 && seg[1] == "roles" && r.Method == http.MethodDelete:
+		member := &p.project.Members[memberIndex]
+		roles := member.Roles[:0]
+		for _, role := range member.Roles {
+			if role.Name != seg[2] {
+				roles = append(roles, role)
+			}
+		}
+		member.Roles = roles
+		writeJSON(w, map[string]bool{"deleted": true})
+	case len(seg) == 1 && r.Method == http.MethodDelete:
+		p.project.Members = append(p.project.Members[:memberIndex], p.project.Members[memberIndex+1:]...)
+		writeJSON(w, map[string]bool{"deleted": true})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (m *mockSanity) serveInvitations(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string) {
+	switch {
+	case len(seg) == 0 && r.Method == http.MethodGet:
+		writeJSON(w, append([]Invitation{}, p.invitations...))
+	case len(seg) == 1 && r.Method == http.MethodDelete:
+		for i, inv := range p.invitations {
+			if inv.Id == seg[0] {
+				p.invitations[i].IsRevoked = true
+				writeJSON(w, map[string]bool{"deleted": true})
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "Invitation not found")
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (m *mockSanity) serveWebhooks(w http.ResponseWriter, r *http.Request, p *mockProject, seg []string, body []byte) {
+	if len(seg) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, append([]Webhook{}, p.webhooks...))
+		case http.MethodPost:
+			var webhook Webhook
+			if !decode(w, body, &webhook) {
+				return
+			}
+			m.nextId++
+			webhook.Id = fmt.Sprintf("hook%d", m.nextId)
+			p.webhooks = append(p.webhooks, webhook)
+			writeJSON(w, webhook)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+
+	index := -1
+	for i, webhook := range p.webhooks {
+		if webhook.Id == seg[0] {
+			index = i
+		}
+	}
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "Webhook not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, p.webhooks[index])
+	case http.MethodPatch:
+		// a PATCH only changes the fields that are sent
+		current, _ := json.Marshal(p.webhooks[index])
+		var merged map[string]json.RawMessage
+		_ = json.Unmarshal(current, &merged)
+		var patch map[string]json.RawMessage
+		if !decode(w, body, &patch) {
+			return
+		}
+		for key, value := range patch {
+			merged[key] = value
+		}
+		b, _ := json.Marshal(merged)
+		var webhook Webhook
+		if !decode(w, b, &webhook) {
+			return
+		}
+		webhook.Id = seg[0]
+		p.webhooks[index] = webhook
+		writeJSON(w, webhook)
+	case http.MethodDelete:
+		p.webhooks = append(p.webhooks[:index], p.webhooks[index+1:]...)
+		writeJSON(w, map[string]interface{}{"id": seg[0], "deleted": true})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// match reports whether the path segments match the pattern, in which `*`
+// matches any segment.
+func match(seg []string, pattern ...string) bool {
+	if len(seg) != len(pattern) {
+		return false
+	}
+	for i := range seg {
+		if pattern[i] != "*" && pattern[i] != seg[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func decode(w http.ResponseWriter, body []byte, v interface{}) bool {
+	if len(body) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Request-Id", "req-mock")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"statusCode": status, "message": message})
+}
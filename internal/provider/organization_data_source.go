@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &OrganizationDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &OrganizationDataSource{}
+
+func NewOrganizationDataSource() datasource.DataSource {
+	return &OrganizationDataSource{}
+}
+
+// OrganizationDataSource defines the data source implementation.
+type OrganizationDataSource struct {
+	client *Client
+}
+
+// OrganizationDataSourceModel describes the data source data model.
+type OrganizationDataSourceModel struct {
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Slug types.String `tfsdk:"slug"`
+}
+
+func (d *OrganizationDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organization"
+}
+
+func (d *OrganizationDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets a Sanity organization by its slug or name. Use the `id` as the `organization` of a `sanity_project`.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "The unique organization ID generated by Sanity.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"name": {
+				MarkdownDescription: "The name of the organization. Exactly one of `name` or `slug` must be set.",
+				Type:                types.StringType,
+				Optional:            true,
+				Computed:            true,
+			},
+			"slug": {
+				MarkdownDescription: "The URL-friendly identifier of the organization. Exactly one of `name` or `slug` must be set.",
+				Type:                types.StringType,
+				Optional:            true,
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (d *OrganizationDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data OrganizationDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Name.Unknown || data.Slug.Unknown {
+		return
+	}
+
+	if data.Name.Null == data.Slug.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("slug"),
+			"Invalid Attribute Combination",
+			"Exactly one of name or slug must be set to look up an organization",
+		)
+	}
+}
+
+func (d *OrganizationDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *OrganizationDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OrganizationDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizations, err := d.client.ListOrganizations(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var matches []Organization
+	for _, org := range organizations {
+		// slugs are unique, but names are not
+		if !data.Slug.Null && org.Slug == data.Slug.Value {
+			matches = append(matches, org)
+		} else if !data.Name.Null && org.Name == data.Name.Value {
+			matches = append(matches, org)
+		}
+	}
+
+	lookup := fmt.Sprintf("the slug %q", data.Slug.Value)
+	if data.Slug.Null {
+		lookup = fmt.Sprintf("the name %q", data.Name.Value)
+	}
+
+	if len(matches) == 0 {
+		resp.Diagnostics.AddError("organization not found", fmt.Sprintf("No organization with %s was found", lookup))
+		return
+	}
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError("multiple organizations found", fmt.Sprintf("%d organizations with %s were found. Look the organization up by its slug instead.", len(matches), lookup))
+		return
+	}
+
+	org := matches[0]
+
+	data.Id = types.String{Value: org.Id}
+	data.Name = types.String{Value: org.Name}
+	data.Slug = types.String{Value: org.Slug}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
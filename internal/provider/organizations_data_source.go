@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &OrganizationsDataSource{}
+
+func NewOrganizationsDataSource() datasource.DataSource {
+	return &OrganizationsDataSource{}
+}
+
+// OrganizationsDataSource defines the data source implementation.
+type OrganizationsDataSource struct {
+	client *Client
+}
+
+// OrganizationsDataSourceModel describes the data source data model.
+type OrganizationsDataSourceModel struct {
+	Organizations []OrganizationsDataSourceOrganizationModel `tfsdk:"organizations"`
+}
+
+// OrganizationsDataSourceOrganizationModel describes a single organization in
+// the data source data model.
+type OrganizationsDataSourceOrganizationModel struct {
+	Id   types.String `tfsdk:"id"`
+	Name types.String `tfsdk:"name"`
+	Slug types.String `tfsdk:"slug"`
+}
+
+func (d *OrganizationsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_organizations"
+}
+
+func (d *OrganizationsDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets all the Sanity organizations that the authenticated user is a member of.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"organizations": {
+				MarkdownDescription: "The organizations available to the user.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						MarkdownDescription: "The unique organization ID generated by Sanity.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"name": {
+						MarkdownDescription: "The name of the organization.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"slug": {
+						MarkdownDescription: "The URL-friendly identifier of the organization.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *OrganizationsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *OrganizationsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data OrganizationsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	organizations, err := d.client.ListOrganizations(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Organizations = make([]OrganizationsDataSourceOrganizationModel, 0, len(organizations))
+	for _, org := range organizations {
+		data.Organizations = append(data.Organizations, OrganizationsDataSourceOrganizationModel{
+			Id:   types.String{Value: org.Id},
+			Name: types.String{Value: org.Name},
+			Slug: types.String{Value: org.Slug},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -3,9 +3,12 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/tessellator/go-sanity/sanity"
@@ -13,6 +16,7 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces
 var _ datasource.DataSource = &ProjectDataSource{}
+var _ datasource.DataSourceWithValidateConfig = &ProjectDataSource{}
 
 func NewProjectDataSource() datasource.DataSource {
 	return &ProjectDataSource{}
@@ -20,7 +24,7 @@ func NewProjectDataSource() datasource.DataSource {
 
 // ProjectDataSource defines the data source implementation.
 type ProjectDataSource struct {
-	client *sanity.Client
+	client *Client
 }
 
 // ProjectDataSourceModel describes the data source data model.
@@ -29,9 +33,14 @@ type ProjectDataSourceModel struct {
 	Name                types.String `tfsdk:"name"`
 	Organization        types.String `tfsdk:"organization"`
 	StudioHost          types.String `tfsdk:"studio_host"`
+	StudioURL           types.String `tfsdk:"studio_url"`
 	ExternalStudioHost  types.String `tfsdk:"external_studio_host"`
 	IsDisabledByUser    types.Bool   `tfsdk:"disabled_by_user"`
 	ActivityFeedEnabled types.Bool   `tfsdk:"activity_feed_enabled"`
+	Features            types.Set    `tfsdk:"features"`
+	CreatedAt           types.String `tfsdk:"created_at"`
+	UpdatedAt           types.String `tfsdk:"updated_at"`
+	Metadata            types.Map    `tfsdk:"metadata"`
 }
 
 func (d *ProjectDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
@@ -40,22 +49,25 @@ func (d *ProjectDataSource) Metadata(ctx context.Context, req datasource.Metadat
 
 func (d *ProjectDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
-		MarkdownDescription: "Gets a Sanity project by its ID. A project is the base resource for creating content, and the project may contain datasets, CORS origins, and tags.",
+		MarkdownDescription: "Gets a Sanity project by its ID or by its name. A project is the base resource for creating content, and the project may contain datasets, CORS origins, and tags.",
 
 		Attributes: map[string]tfsdk.Attribute{
 			"id": {
-				MarkdownDescription: "The project ID, which you can find at the top of the project page in Sanity.",
+				MarkdownDescription: "The project ID, which you can find at the top of the project page in Sanity. Exactly one of `id` or `name` must be set.",
 				Type:                types.StringType,
-				Required:            true,
+				Optional:            true,
+				Computed:            true,
 			},
 			"name": {
-				MarkdownDescription: "The project name.",
+				MarkdownDescription: "The project name. When the project is looked up by name, the name must match exactly one project that you have access to; set `organization` to tell projects with the same name apart.",
 				Type:                types.StringType,
+				Optional:            true,
 				Computed:            true,
 			},
 			"organization": {
-				MarkdownDescription: "The name of the organization that owns the project.",
+				MarkdownDescription: "The ID of the organization that owns the project. When the project is looked up by name, only the projects in this organization are considered.",
 				Type:                types.StringType,
+				Optional:            true,
 				Computed:            true,
 			},
 			"studio_host": {
@@ -63,6 +75,11 @@ func (d *ProjectDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.D
 				Type:                types.StringType,
 				Computed:            true,
 			},
+			"studio_url": {
+				MarkdownDescription: "The URL of the studio hosted by Sanity, in the form `https://<studio_host>.sanity.studio`. Empty when the project has no studio host.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
 			"external_studio_host": {
 				MarkdownDescription: "The external studio host URL.",
 				Type:                types.StringType,
@@ -78,22 +95,73 @@ func (d *ProjectDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.D
 				Computed:            true,
 				Type:                types.BoolType,
 			},
+			"features": {
+				MarkdownDescription: "The names of the features that are enabled for the project.",
+				Computed:            true,
+				Type:                types.SetType{ElemType: types.StringType},
+			},
+			"created_at": {
+				MarkdownDescription: "When the project was created, in RFC 3339 format.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"updated_at": {
+				MarkdownDescription: "When the project was last modified, in RFC 3339 format. Empty if Sanity does not report it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"metadata": {
+				MarkdownDescription: "The full metadata of the project, including `color`, `externalStudioHost`, and any custom entries.",
+				Computed:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
 		},
 	}, nil
 }
 
+func (d *ProjectDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var data ProjectDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Id.Unknown || data.Name.Unknown {
+		return
+	}
+
+	if data.Id.Null == data.Name.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Invalid Attribute Combination",
+			"Exactly one of id or name must be set to look up a project",
+		)
+		return
+	}
+
+	if !data.Id.Null && !data.Organization.Null {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("organization"),
+			"Invalid Attribute Combination",
+			"organization can only be set when the project is looked up by name",
+		)
+	}
+}
+
 func (d *ProjectDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*sanity.Client)
+	client, ok := req.ProviderData.(*Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *sanity.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -113,13 +181,19 @@ func (d *ProjectDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	}
 
 	if data.Id.Null {
-		resp.Diagnostics.AddError("Project id is null", "Project id is null")
-		return
+		projectId, diags := d.findByName(ctx, data)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		data.Id = types.String{Value: projectId}
 	}
 
-	project, err := d.client.Projects.Get(ctx, data.Id.Value)
+	project, err := d.client.GetProject(ctx, data.Id.Value)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
@@ -127,9 +201,61 @@ func (d *ProjectDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	data.Name = types.String{Value: project.DisplayName}
 	data.Organization = types.String{Value: project.OrganizationId}
 	data.StudioHost = types.String{Value: project.StudioHost}
+	data.StudioURL = types.String{Value: studioURL(project.StudioHost)}
 	data.ExternalStudioHost = types.String{Value: project.Metadata["externalStudioHost"]}
 	data.IsDisabledByUser = types.Bool{Value: project.IsDisabledByUser}
 	data.ActivityFeedEnabled = types.Bool{Value: project.ActivityFeedEnabled}
+	data.Features = projectFeaturesSet(project.Features)
+	data.Metadata = types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{}}
+	for k, v := range project.Metadata {
+		data.Metadata.Elems[k] = types.String{Value: v}
+	}
+
+	data.CreatedAt = timestampValue(project.CreatedAt)
+	data.UpdatedAt = timestampValue(project.UpdatedAt)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// findByName returns the ID of the only project with the name in the data,
+// limited to the organization in the data when it is set.
+func (d *ProjectDataSource) findByName(ctx context.Context, data ProjectDataSourceModel) (string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	projects, err := d.client.Projects.List(ctx)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return "", diags
+	}
+
+	var matches []sanity.Project
+	for _, project := range projects {
+		if project.DisplayName != data.Name.Value {
+			continue
+		}
+		if !data.Organization.Null && project.OrganizationId != data.Organization.Value {
+			continue
+		}
+		matches = append(matches, project)
+	}
+
+	lookup := fmt.Sprintf("the name %q", data.Name.Value)
+	if !data.Organization.Null {
+		lookup += fmt.Sprintf(" in organization %s", data.Organization.Value)
+	}
+
+	if len(matches) == 0 {
+		diags.AddError("project not found", fmt.Sprintf("No project with %s was found", lookup))
+		return "", diags
+	}
+	if len(matches) > 1 {
+		ids := make([]string, 0, len(matches))
+		for _, project := range matches {
+			ids = append(ids, project.Id)
+		}
+		diags.AddError("multiple projects found", fmt.Sprintf("%d projects with %s were found (%s). Set organization or look the project up by its id instead.", len(matches), lookup, strings.Join(ids, ", ")))
+		return "", diags
+	}
+
+	return matches[0].Id, diags
+}
@@ -0,0 +1,402 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+var _ resource.Resource = &ProjectMemberResource{}
+var _ resource.ResourceWithImportState = &ProjectMemberResource{}
+
+func NewProjectMemberResource() resource.Resource {
+	return &ProjectMemberResource{}
+}
+
+type ProjectMemberResource struct {
+	client *Client
+}
+
+type ProjectMemberResourceModel struct {
+	Project  types.String `tfsdk:"project"`
+	MemberId types.String `tfsdk:"member_id"`
+	Role     types.String `tfsdk:"role"`
+	Status   types.String `tfsdk:"status"`
+	Token    types.String `tfsdk:"token"`
+}
+
+const (
+	memberStatusActive  = "active"
+	memberStatusPending = "pending"
+)
+
+func (r *ProjectMemberResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_member"
+}
+
+func (r *ProjectMemberResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Provides a member of a Sanity project. A member is a Sanity user that has been granted a role on the project. A user that has to accept an invitation first is tracked as `pending` until they do.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this member instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
+			"project": {
+				Required:            true,
+				MarkdownDescription: "The ID of the project that the member belongs to.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"member_id": {
+				Required:            true,
+				MarkdownDescription: "The Sanity user ID of the member.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"role": {
+				Required:            true,
+				MarkdownDescription: "The name of the role assigned to the member (e.g. `administrator`, `editor`, or `viewer`). Changing the role updates the member in place.",
+				Type:                types.StringType,
+			},
+			"status": {
+				Computed:            true,
+				MarkdownDescription: "`active` once the user is a member of the project, or `pending` while the user has not accepted the invitation that some roles require.",
+				Type:                types.StringType,
+			},
+		},
+	}, nil
+}
+
+func (r *ProjectMemberResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *ProjectMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *ProjectMemberResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity project member", map[string]interface{}{"project": data.Project.Value, "member_id": data.MemberId.Value})
+
+	// The user may already be a member of the project, in which case the
+	// membership is adopted and only the role is reconciled.
+	member, err := r.findMember(ctx, data.Project.Value, data.MemberId.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	err = r.setRole(ctx, data.Project.Value, data.MemberId.Value, member, data.Role.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.readStatus(ctx, data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProjectMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *ProjectMemberResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity project member", map[string]interface{}{"project": data.Project.Value, "member_id": data.MemberId.Value})
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+	if data.MemberId.Null {
+		resp.Diagnostics.AddError("Member id is null", "Member id is null")
+		return
+	}
+
+	member, invitation, err := r.findMembership(ctx, data.Project.Value, data.MemberId.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+	if member == nil && invitation == nil {
+		// the user was removed from the project outside of Terraform
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if member == nil {
+		if invitation.Role != "" {
+			data.Role = types.String{Value: invitation.Role}
+		}
+		data.Status = types.String{Value: memberStatusPending}
+
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
+	}
+
+	if !hasRole(member, data.Role.Value) && len(member.Roles) > 0 {
+		data.Role = types.String{Value: member.Roles[0].Name}
+	}
+	data.Status = types.String{Value: memberStatusActive}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProjectMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *ProjectMemberResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity project member", map[string]interface{}{"project": data.Project.Value, "member_id": data.MemberId.Value})
+
+	member, err := r.findMember(ctx, data.Project.Value, data.MemberId.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	err = r.setRole(ctx, data.Project.Value, data.MemberId.Value, member, data.Role.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	resp.Diagnostics.Append(r.readStatus(ctx, data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *ProjectMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *ProjectMemberResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity project member", map[string]interface{}{"project": data.Project.Value, "member_id": data.MemberId.Value})
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+	if data.MemberId.Null {
+		resp.Diagnostics.AddError("Member id is null", "Member id is null")
+		return
+	}
+
+	// a pending invitation is cancelled so that it can no longer be accepted
+	invitation, err := r.findInvitation(ctx, data.Project.Value, data.MemberId.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+	if invitation != nil {
+		err = r.client.RevokeInvitation(ctx, data.Project.Value, invitation.Id)
+		if err != nil && !isNotFound(err) {
+			resp.Diagnostics.Append(clientErrorDiagnosticf(err, "invitation %s could not be revoked", invitation.Id))
+			return
+		}
+	}
+
+	_, err = r.client.RemoveMember(ctx, data.Project.Value, data.MemberId.Value)
+
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "member %s could not be removed", data.MemberId.Value))
+		return
+	}
+}
+
+func (r *ProjectMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectId, memberId, _ := strings.Cut(req.ID, "/")
+	if projectId == "" || memberId == "" {
+		resp.Diagnostics.AddError("Import Error", "The format for importing a project member is project-id/member-id")
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), resource.ImportStateRequest{ID: projectId}, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("member_id"), resource.ImportStateRequest{ID: memberId}, resp)
+}
+
+// readStatus sets the status of the member after its role was set. A user that
+// has to accept an invitation first is pending until they do.
+func (r *ProjectMemberResource) readStatus(ctx context.Context, data *ProjectMemberResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	member, invitation, err := r.findMembership(ctx, data.Project.Value, data.MemberId.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	switch {
+	case member != nil:
+		data.Status = types.String{Value: memberStatusActive}
+	case invitation != nil:
+		data.Status = types.String{Value: memberStatusPending}
+	default:
+		diags.AddError(
+			"Member Not Added",
+			fmt.Sprintf("User %s was given the role %s in project %s, but is neither a member of the project nor invited to it. Check that the member ID is a Sanity user ID.", data.MemberId.Value, data.Role.Value, data.Project.Value),
+		)
+	}
+
+	return diags
+}
+
+// findMembership returns the project member with the given user ID or, if the
+// user is not a member, the invitation that the user has not accepted yet.
+// Both are nil if the user is neither.
+func (r *ProjectMemberResource) findMembership(ctx context.Context, projectId string, memberId string) (*sanity.Member, *Invitation, error) {
+	member, err := r.findMember(ctx, projectId, memberId)
+	if err != nil || member != nil {
+		return member, nil, err
+	}
+
+	invitation, err := r.findInvitation(ctx, projectId, memberId)
+	return nil, invitation, err
+}
+
+// findMember returns the project member with the given user ID, or nil if the
+// user is not a member of the project.
+func (r *ProjectMemberResource) findMember(ctx context.Context, projectId string, memberId string) (*sanity.Member, error) {
+	project, err := r.client.Projects.Get(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range project.Members {
+		if m.Id == memberId {
+			member := m
+			return &member, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// findInvitation returns the pending invitation for the user, or nil if the
+// user has no invitation waiting to be accepted.
+func (r *ProjectMemberResource) findInvitation(ctx context.Context, projectId string, memberId string) (*Invitation, error) {
+	invitations, err := r.client.ListInvitations(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range invitations {
+		if i.UserId == memberId && !i.IsAccepted && !i.IsRevoked {
+			invitation := i
+			return &invitation, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// setRole assigns the role to the member and removes any other roles the
+// member currently holds.
+func (r *ProjectMemberResource) setRole(ctx context.Context, projectId string, memberId string, member *sanity.Member, role string) error {
+	if member == nil || !hasRole(member, role) {
+		err := r.client.AddMemberRole(ctx, projectId, memberId, role)
+		if err != nil {
+			return err
+		}
+	}
+
+	if member == nil {
+		return nil
+	}
+
+	for _, existing := range member.Roles {
+		if existing.Name == role {
+			continue
+		}
+		_, err := r.client.RemoveMemberRole(ctx, projectId, memberId, existing.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func hasRole(member *sanity.Member, role string) bool {
+	for _, r := range member.Roles {
+		if r.Name == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// projectMemberPlan returns the plan for a project member.
+func projectMemberPlan(project string, memberId string, role string) ProjectMemberResourceModel {
+	return ProjectMemberResourceModel{
+		Project:  types.String{Value: project},
+		MemberId: types.String{Value: memberId},
+		Role:     types.String{Value: role},
+		Status:   types.String{Unknown: true},
+		Token:    types.String{Null: true},
+	}
+}
+
+func TestProjectMemberResource(t *testing.T) {
+	cases := []struct {
+		name  string
+		setup func(m *mockSanity)
+		test  func(t *testing.T, m *mockSanity, rt *resourceTest)
+	}{
+		{
+			name: "pending until the invitation is accepted",
+			setup: func(m *mockSanity) {
+				m.invitees["user1"] = true
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(projectMemberPlan("p1", "user1", "editor"))
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[ProjectMemberResourceModel](t, state); data.Status.Value != memberStatusPending {
+					t.Fatalf("expected a pending member after create, got %+v", data)
+				}
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[ProjectMemberResourceModel](t, state); data.Status.Value != memberStatusPending || data.Role.Value != "editor" {
+					t.Fatalf("expected a pending editor after read, got %+v", data)
+				}
+
+				m.acceptInvitation("p1", "user1")
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[ProjectMemberResourceModel](t, state); data.Status.Value != memberStatusActive || data.Role.Value != "editor" {
+					t.Fatalf("expected an active editor after the invitation was accepted, got %+v", data)
+				}
+
+				state, diags = rt.update(state, projectMemberPlan("p1", "user1", "viewer"))
+				requireNoDiagnostics(t, diags)
+				if data := stateModel[ProjectMemberResourceModel](t, state); data.Status.Value != memberStatusActive {
+					t.Fatalf("expected an active member after update, got %+v", data)
+				}
+			},
+		},
+		{
+			name: "deleting a pending member revokes the invitation",
+			setup: func(m *mockSanity) {
+				m.invitees["user1"] = true
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(projectMemberPlan("p1", "user1", "editor"))
+				requireNoDiagnostics(t, diags)
+
+				requireNoDiagnostics(t, rt.delete(state))
+
+				var revoked bool
+				m.withProject("p1", func(p *mockProject) {
+					revoked = len(p.invitations) == 1 && p.invitations[0].IsRevoked
+				})
+				if !revoked {
+					t.Fatal("expected the invitation to be revoked")
+				}
+			},
+		},
+		{
+			name: "a user that is neither a member nor invited",
+			setup: func(m *mockSanity) {
+				m.handle("PUT", "/projects/p1/members/unknown/roles/editor", func(w http.ResponseWriter, r *http.Request) bool {
+					writeJSON(w, map[string]bool{"created": true})
+					return true
+				})
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				_, diags := rt.create(projectMemberPlan("p1", "unknown", "editor"))
+				requireErrorDiagnostic(t, diags, "is neither a member of the project nor invited to it")
+			},
+		},
+		{
+			name: "a forbidden request names the missing role",
+			setup: func(m *mockSanity) {
+				m.fail("PUT", "/projects/p1/members/[^/]+/roles/[^/]+", http.StatusForbidden)
+			},
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				_, diags := rt.create(projectMemberPlan("p1", "user1", "editor"))
+				requireErrorDiagnostic(t, diags, "Sanity API Error: 403 Forbidden")
+				requireErrorDiagnostic(t, diags, "Managing project members requires a token with the administrator role.")
+			},
+		},
+		{
+			name: "removed outside of Terraform",
+			test: func(t *testing.T, m *mockSanity, rt *resourceTest) {
+				state, diags := rt.create(projectMemberPlan("p1", "user1", "editor"))
+				requireNoDiagnostics(t, diags)
+
+				m.withProject("p1", func(p *mockProject) {
+					p.project.Members = p.project.Members[:1]
+				})
+
+				state, diags = rt.read(state)
+				requireNoDiagnostics(t, diags)
+				if !state.Raw.IsNull() {
+					t.Fatalf("expected the member to be removed from state, got %v", state.Raw)
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			m.addProject("p1", "Test")
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			tc.test(t, m, newResourceTest(t, m, NewProjectMemberResource()))
+		})
+	}
+}
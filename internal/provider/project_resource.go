@@ -3,7 +3,11 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
@@ -12,17 +16,42 @@ import (
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/tessellator/go-sanity/sanity"
 	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_plan_modifier"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_validator"
 )
 
 var _ resource.Resource = &ProjectResource{}
 var _ resource.ResourceWithImportState = &ProjectResource{}
+var _ resource.ResourceWithUpgradeState = &ProjectResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectResource{}
+
+var colorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+var projectIdRegexp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+var nonEmptyRegexp = regexp.MustCompile(`\S`)
+
+var studioHostRegexp = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]*[a-z0-9])?$`)
+
+var deleteBehaviorRegexp = regexp.MustCompile(`^(delete|archive)$`)
+
+var datasetAclModeRegexp = regexp.MustCompile(`^(public|private)$`)
+
+const (
+	projectDeleteBehaviorDelete  = "delete"
+	projectDeleteBehaviorArchive = "archive"
+)
+
+// reservedMetadataKeys are the metadata entries that are managed with their
+// own attributes. Sanity reads the external studio host from
+// `externalStudioHost` but go-sanity writes it to `externalHost`.
+var reservedMetadataKeys = []string{"color", "externalHost", "externalStudioHost"}
 
 func NewProjectResource() resource.Resource {
 	return &ProjectResource{}
 }
 
 type ProjectResource struct {
-	client *sanity.Client
+	client *Client
 }
 
 // ProjectResourceModel describes the resource data model.
@@ -31,10 +60,29 @@ type ProjectResourceModel struct {
 	Name                types.String `tfsdk:"name"`
 	Organization        types.String `tfsdk:"organization"`
 	StudioHost          types.String `tfsdk:"studio_host"`
+	StudioURL           types.String `tfsdk:"studio_url"`
 	ExternalStudioHost  types.String `tfsdk:"external_studio_host"`
 	Color               types.String `tfsdk:"color"`
+	Metadata            types.Map    `tfsdk:"metadata"`
+	AllMetadata         types.Map    `tfsdk:"all_metadata"`
 	IsDisabledByUser    types.Bool   `tfsdk:"disabled_by_user"`
 	ActivityFeedEnabled types.Bool   `tfsdk:"activity_feed_enabled"`
+	Features            types.Set    `tfsdk:"features"`
+	CreatedAt           types.String `tfsdk:"created_at"`
+	UpdatedAt           types.String `tfsdk:"updated_at"`
+	ManageDefaultCORS   types.Bool   `tfsdk:"manage_default_cors"`
+	DeletionProtection  types.Bool   `tfsdk:"deletion_protection"`
+	DeleteBehavior      types.String `tfsdk:"delete_behavior"`
+	StudioCORSOriginId  types.String `tfsdk:"studio_cors_origin_id"`
+	Token               types.String `tfsdk:"token"`
+
+	Datasets       []ProjectResourceDatasetModel `tfsdk:"dataset"`
+	InitialDataset []ProjectResourceDatasetModel `tfsdk:"initial_dataset"`
+}
+
+type ProjectResourceDatasetModel struct {
+	Name    types.String `tfsdk:"name"`
+	AclMode types.String `tfsdk:"acl_mode"`
 }
 
 func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -44,8 +92,15 @@ func (r *ProjectResource) Metadata(ctx context.Context, req resource.MetadataReq
 func (r *ProjectResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
 		MarkdownDescription: "Provides a Sanity project. A project is the base resource for creating content, and the project may contain datasets, CORS origins, and tags.",
+		Version:             1,
 
 		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this project instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
 			"id": {
 				Computed:            true,
 				MarkdownDescription: "The project ID, which you can find at the top of the project page in Sanity.",
@@ -64,7 +119,7 @@ func (r *ProjectResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Dia
 				},
 			},
 			"organization": {
-				MarkdownDescription: "The name of the organization that owns the project.",
+				MarkdownDescription: "The ID of the organization that owns the project. Defaults to the `organization` of the provider. When neither is set, a personal project that belongs to no organization is created, and this attribute is empty. Changing the organization transfers the project to the new organization; a project cannot be moved out of an organization.",
 				Optional:            true,
 				Computed:            true,
 				Type:                types.StringType,
@@ -73,33 +128,57 @@ func (r *ProjectResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Dia
 				},
 			},
 			"studio_host": {
-				MarkdownDescription: "The studio host URL. This attribute exhibits two unique behaviors that are important to note. First, once the studio host URL is set, it may not be changed. Changing this value will force a replacement. Second, when the studio host is set, Sanity will automatically create a CORS entry for the studio host URL. This means that it is not necessary for you to create a CORS entry, and you will get a conflict error if you do.",
+				MarkdownDescription: "The studio host, which is the subdomain prefix of the studio hosted by Sanity: `foo` for `https://foo.sanity.studio`. This attribute exhibits two unique behaviors that are important to note. First, once the studio host URL is set, it may not be changed. Setting it on a project that has no studio host updates the project in place, but changing it afterwards will force a replacement. Second, when the studio host is set, Sanity will automatically create a CORS entry for the studio host URL. This means that it is not necessary for you to create a CORS entry; a `sanity_cors_origin` for the studio URL adopts the existing entry, whose ID is available as `studio_cors_origin_id`.",
 				Optional:            true,
 				Computed:            true,
 				Type:                types.StringType,
 				PlanModifiers: tfsdk.AttributePlanModifiers{
 					resource.UseStateForUnknown(),
-					resource.RequiresReplace(),
+					attribute_plan_modifier.RequiresReplaceIfWasEmpty(),
+				},
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.StringMatches(studioHostRegexp, "The studio host must be only the subdomain prefix of the studio URL, e.g. \"foo\" for https://foo.sanity.studio, without a scheme or the .sanity.studio suffix"),
 				},
 			},
+			"studio_url": {
+				MarkdownDescription: "The URL of the studio hosted by Sanity, in the form `https://<studio_host>.sanity.studio`. Empty when no studio host is set.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"studio_cors_origin_id": {
+				MarkdownDescription: "The ID of the CORS entry that Sanity created for the studio host, or an empty string if there is none. A `sanity_cors_origin` for the studio URL adopts this entry rather than conflicting with it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
 			"external_studio_host": {
-				MarkdownDescription: "The external studio host URL.",
+				MarkdownDescription: "The external studio host URL. Removing it from the configuration clears the external studio host of the project.",
 				Optional:            true,
-				Computed:            true,
 				Type:                types.StringType,
-				PlanModifiers: tfsdk.AttributePlanModifiers{
-					resource.UseStateForUnknown(),
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.StringMatches(nonEmptyRegexp, "The external studio host cannot be empty; remove the attribute to clear it"),
 				},
 			},
 			"color": {
-				MarkdownDescription: "The hex value for the project color. This is the color of the project icon at https://sanity.io/manage.",
+				MarkdownDescription: "The hex value for the project color, in the form `#rrggbb`. This is the color of the project icon at https://sanity.io/manage. Removing it from the configuration resets the project to the default color.",
 				Optional:            true,
-				Computed:            true,
 				Type:                types.StringType,
-				PlanModifiers: tfsdk.AttributePlanModifiers{
-					resource.UseStateForUnknown(),
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.StringMatches(colorRegexp, "The color must be a hex value in the form #rrggbb"),
 				},
 			},
+			"metadata": {
+				MarkdownDescription: "Custom metadata entries of the project. Only the entries listed here are managed: an entry removed from the configuration is cleared, and entries set outside of Terraform are left alone and shown in `all_metadata`. The `color` and external studio host are also stored in the metadata, so their keys (`color`, `externalHost` and `externalStudioHost`) cannot be used here; use the `color` and `external_studio_host` attributes instead.",
+				Optional:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.MapKeysNotIn(reservedMetadataKeys, "The key is managed with its own attribute"),
+				},
+			},
+			"all_metadata": {
+				MarkdownDescription: "All of the metadata entries of the project, including the `color`, the external studio host, and the entries that are not managed with `metadata`.",
+				Computed:            true,
+				Type:                types.MapType{ElemType: types.StringType},
+			},
 			"disabled_by_user": {
 				MarkdownDescription: "Indicates whether the project is archived. Defaults to `false`.",
 				Optional:            true,
@@ -118,6 +197,108 @@ func (r *ProjectResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Dia
 					attribute_plan_modifier.DefaultValue(types.Bool{Value: true}),
 				},
 			},
+			"features": {
+				MarkdownDescription: "The names of the features that are enabled for the project. Features come with the plan of the project and cannot be toggled through the API, so this attribute is read-only; use `activity_feed_enabled` to toggle the activity feed.",
+				Computed:            true,
+				Type:                types.SetType{ElemType: types.StringType},
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"created_at": {
+				MarkdownDescription: "When the project was created, in RFC 3339 format.",
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+			"updated_at": {
+				MarkdownDescription: "When the project was last modified, in RFC 3339 format. Empty if Sanity does not report it.",
+				Computed:            true,
+				Type:                types.StringType,
+			},
+			"manage_default_cors": {
+				MarkdownDescription: "Indicates whether the CORS entries that Sanity creates for a new project are removed, so that all CORS origins can be managed with `sanity_cors_origin`. Set to `false` to keep them. This only applies when the project is created. The CORS entry that Sanity creates for the `studio_host` is added after the default entries are removed, so it is kept either way. Defaults to `true`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.Bool{Value: true}),
+				},
+			},
+			"delete_behavior": {
+				MarkdownDescription: "What happens to the project when the resource is destroyed. With `delete`, the project and all of its datasets and documents are permanently deleted. With `archive`, the project is only archived by setting `disabled_by_user`, and it is removed from the Terraform state; it can be restored at https://sanity.io/manage or imported again. `deletion_protection` applies to both. Defaults to `delete`.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.String{Value: projectDeleteBehaviorDelete}),
+				},
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.StringMatches(deleteBehaviorRegexp, "The delete behavior must be either delete or archive"),
+				},
+			},
+			"deletion_protection": {
+				MarkdownDescription: "Indicates whether the project is protected from being deleted. While it is `true`, destroying or replacing the project fails, even when the resource is removed from the configuration; set it to `false` and apply before deleting the project. Defaults to the value of the `SANITY_DELETION_PROTECTION` environment variable, or `false` if it is not set, so that a pipeline can protect all of the projects that it manages.",
+				Optional:            true,
+				Computed:            true,
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.EnvDefaultBool("SANITY_DELETION_PROTECTION", false),
+				},
+			},
+		},
+
+		Blocks: map[string]tfsdk.Block{
+			"dataset": {
+				MarkdownDescription: "A dataset that is managed together with the project. Datasets that are not listed are left alone, and removing a block deletes its dataset. Do not manage a dataset both here and with a `sanity_dataset` resource.",
+				NestingMode:         tfsdk.BlockNestingModeSet,
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Required:            true,
+						MarkdownDescription: "The name of the dataset.",
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							attribute_validator.StringMatches(datasetNameRegexp, "The dataset name may only contain lowercase letters, numbers, underscores, and dashes, must start with a letter or number, and can be at most 64 characters long"),
+						},
+					},
+					"acl_mode": {
+						Required:            true,
+						MarkdownDescription: "The ACL mode of the dataset, either `public` or `private`.",
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							attribute_validator.StringMatches(datasetAclModeRegexp, "The ACL mode must be either public or private"),
+						},
+					},
+				},
+			},
+			"initial_dataset": {
+				MarkdownDescription: "A dataset that is created together with the project, so that the project is usable right away. This is a create-time convenience: the dataset is not refreshed or updated afterwards, and it can be managed with a `sanity_dataset` resource or deleted like any other dataset. If the dataset cannot be created, the project is deleted again. Changing or removing the block replaces the project, except on an imported project, which has no initial dataset in state.",
+				NestingMode:         tfsdk.BlockNestingModeList,
+				MaxItems:            1,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplaceIf(initialDatasetChanged, "Requires replacement if the initial dataset changes after the project was created", "Requires replacement if the initial dataset changes after the project was created"),
+				},
+				Attributes: map[string]tfsdk.Attribute{
+					"name": {
+						Required:            true,
+						MarkdownDescription: "The name of the dataset.",
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							attribute_validator.StringMatches(datasetNameRegexp, "The dataset name may only contain lowercase letters, numbers, underscores, and dashes, must start with a letter or number, and can be at most 64 characters long"),
+						},
+					},
+					"acl_mode": {
+						Required:            true,
+						MarkdownDescription: "The ACL mode of the dataset, either `public` or `private`.",
+						Type:                types.StringType,
+						Validators: []tfsdk.AttributeValidator{
+							attribute_validator.StringMatches(datasetAclModeRegexp, "The ACL mode must be either public or private"),
+						},
+					},
+				},
+			},
 		},
 	}, nil
 }
@@ -128,12 +309,12 @@ func (r *ProjectResource) Configure(ctx context.Context, req resource.ConfigureR
 		return
 	}
 
-	client, ok := req.ProviderData.(*sanity.Client)
+	client, ok := req.ProviderData.(*Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -142,7 +323,23 @@ func (r *ProjectResource) Configure(ctx context.Context, req resource.ConfigureR
 	r.client = client
 }
 
+func (r *ProjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() || req.Plan.Raw.Equal(req.State.Raw) {
+		return
+	}
+
+	// Terraform only marks updated_at as unknown when the configuration
+	// changed, not when a plan modifier such as a default changed the plan,
+	// but every update modifies the project
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("updated_at"), types.String{Unknown: true})...)
+}
+
 func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *ProjectResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -151,72 +348,531 @@ func (r *ProjectResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity project", map[string]interface{}{"name": data.Name.Value})
+
+	// an empty organization creates a personal project, as the organization
+	// ID is left out of the request
+	organization := data.Organization.Value
+	if data.Organization.Null || data.Organization.Unknown {
+		organization = r.client.defaultOrganization
+	}
+
+	// Creating a project takes six steps: the project is created, the project
+	// is waited on until it is ready, the CORS entries that Sanity adds to new
+	// projects are removed (unless they are kept with manage_default_cors), the
+	// remaining settings are applied, the initial dataset is created, and the
+	// inline datasets are created. If a later step fails, the project is
+	// deleted again so that a retry does not leave an orphaned project behind.
 	project, err := r.client.Projects.Create(ctx, &sanity.CreateProjectRequest{
 		DisplayName:    data.Name.Value,
-		OrganizationId: data.Organization.Value,
+		OrganizationId: organization,
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
-	entries, err := r.client.Projects.ListCORSEntries(ctx, project.Id)
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+	projectId := project.Id
+
+	tflog.Trace(ctx, "created a sanity project", map[string]interface{}{"id": projectId, "name": project.DisplayName})
+
+	diags := r.waitForProject(ctx, projectId)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		r.rollbackCreate(ctx, projectId, &resp.Diagnostics)
 		return
 	}
-	for _, entry := range entries {
-		_, err = r.client.Projects.DeleteCORSEntry(ctx, project.Id, entry.Id)
+
+	if data.ManageDefaultCORS.Value {
+		err = r.removeDefaultCORSEntries(ctx, projectId)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			r.rollbackCreate(ctx, projectId, &resp.Diagnostics)
+			return
+		}
+	}
+
+	if updateReq := newProjectUpdateRequest(data); updateReq != nil {
+		project, err = r.client.Projects.Update(ctx, projectId, updateReq)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			r.rollbackCreate(ctx, projectId, &resp.Diagnostics)
+			return
+		}
+	}
+
+	if metadata := metadataChanges(types.Map{Null: true}, data.Metadata); len(metadata) > 0 {
+		project, err = r.client.UpdateProjectMetadata(ctx, projectId, metadata)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			r.rollbackCreate(ctx, projectId, &resp.Diagnostics)
+			return
+		}
+	}
+
+	for _, d := range data.InitialDataset {
+		_, err = r.client.Projects.CreateDataset(ctx, projectId, &sanity.CreateDatasetRequest{
+			Name:    d.Name.Value,
+			AclMode: d.AclMode.Value,
+		})
 		if err != nil {
-			resp.Diagnostics.AddError("Client Error", err.Error())
-			r.client.Projects.Delete(ctx, project.Id)
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			r.rollbackCreate(ctx, projectId, &resp.Diagnostics)
 			return
 		}
 	}
 
+	diags = r.reconcileDatasets(ctx, projectId, nil, data.Datasets)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		r.rollbackCreate(ctx, projectId, &resp.Diagnostics)
+		return
+	}
+
+	data.fromSanity(project)
+
+	resp.Diagnostics.Append(r.readTimestamps(ctx, data)...)
+	resp.Diagnostics.Append(r.readStudioCORSOrigin(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// fromSanity copies the fields of the project into the model. Create, Read,
+// and Update all go through it, so that they produce the same state for the
+// same project. The color and metadata keep the configured spelling and keys
+// of the model.
+func (m *ProjectResourceModel) fromSanity(project *sanity.Project) {
+	m.Id = types.String{Value: project.Id}
+	m.Name = types.String{Value: project.DisplayName}
+	m.Organization = types.String{Value: project.OrganizationId}
+	m.StudioHost = types.String{Value: normalizeStudioHost(project.StudioHost)}
+	m.StudioURL = types.String{Value: studioURL(m.StudioHost.Value)}
+	m.ExternalStudioHost = externalStudioHostValue(project)
+	m.Color = colorValue(m.Color, project)
+	m.Metadata = metadataValue(m.Metadata, project)
+	m.AllMetadata = allMetadataValue(project)
+	m.IsDisabledByUser = types.Bool{Value: project.IsDisabledByUser}
+	m.ActivityFeedEnabled = types.Bool{Value: project.ActivityFeedEnabled}
+	m.Features = projectFeaturesSet(project.Features)
+}
+
+// setNullDefaults sets the attributes that have a default in the schema, but
+// are null in state written by an older version of the provider or by an
+// import, to their defaults. Without it, the first plan after an upgrade
+// would show them changing from null.
+func (m *ProjectResourceModel) setNullDefaults() {
+	if m.ManageDefaultCORS.Null {
+		m.ManageDefaultCORS = types.Bool{Value: true}
+	}
+	if m.DeletionProtection.Null {
+		m.DeletionProtection = types.Bool{Value: false}
+	}
+	if m.DeleteBehavior.Null {
+		m.DeleteBehavior = types.String{Value: projectDeleteBehaviorDelete}
+	}
+}
+
+func projectFeaturesSet(features []string) types.Set {
+	set := types.Set{ElemType: types.StringType, Elems: []attr.Value{}}
+	for _, feature := range features {
+		set.Elems = append(set.Elems, types.String{Value: feature})
+	}
+	return set
+}
+
+// studioURL returns the URL of the studio hosted by Sanity at the studio host,
+// or an empty string if there is no studio host.
+func studioURL(studioHost string) string {
+	if studioHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s.sanity.studio", studioHost)
+}
+
+// externalStudioHostValue returns the external studio host of the project, or
+// null if it has none.
+func externalStudioHostValue(project *sanity.Project) types.String {
+	host := project.Metadata["externalStudioHost"]
+	if host == "" {
+		return types.String{Null: true}
+	}
+	return types.String{Value: host}
+}
+
+// colorValue returns the color of the project, or null if it has the default
+// color. Sanity stores colors in lower case, so the current value is kept when
+// it only differs in case.
+func colorValue(current types.String, project *sanity.Project) types.String {
+	color := project.Metadata["color"]
+	if color == "" {
+		return types.String{Null: true}
+	}
+	if strings.EqualFold(current.Value, color) {
+		return current
+	}
+	return types.String{Value: color}
+}
+
+// metadataValue returns the entries of the project metadata that are managed
+// by `current`, or null if no entries are managed. Entries that are no longer
+// set in Sanity are left out, so that the drift shows up in the plan.
+func metadataValue(current types.Map, project *sanity.Project) types.Map {
+	if current.Null {
+		return current
+	}
+
+	m := types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{}}
+	for key := range current.Elems {
+		if value, ok := project.Metadata[key]; ok && value != "" {
+			m.Elems[key] = types.String{Value: value}
+		}
+	}
+	return m
+}
+
+func allMetadataValue(project *sanity.Project) types.Map {
+	m := types.Map{ElemType: types.StringType, Elems: map[string]attr.Value{}}
+	for key, value := range project.Metadata {
+		m.Elems[key] = types.String{Value: value}
+	}
+	return m
+}
+
+// metadataChanges returns the metadata entries to send to Sanity to go from
+// the prior to the planned `metadata`. Entries that were removed map to an
+// empty value, which clears them.
+func metadataChanges(prior types.Map, planned types.Map) map[string]string {
+	changes := map[string]string{}
+
+	for key, value := range planned.Elems {
+		v, ok := value.(types.String)
+		if !ok || v.Unknown {
+			continue
+		}
+		if p, ok := prior.Elems[key].(types.String); ok && p.Value == v.Value {
+			continue
+		}
+		changes[key] = v.Value
+	}
+	for key := range prior.Elems {
+		if _, ok := planned.Elems[key]; !ok {
+			changes[key] = ""
+		}
+	}
+
+	return changes
+}
+
+// normalizeStudioHost reduces a studio host to its subdomain prefix, so that a
+// host reported as a full URL such as `https://foo.sanity.studio` is stored as
+// `foo`.
+func normalizeStudioHost(studioHost string) string {
+	studioHost = strings.TrimPrefix(studioHost, "https://")
+	studioHost = strings.TrimPrefix(studioHost, "http://")
+	studioHost = strings.TrimSuffix(studioHost, "/")
+	return strings.TrimSuffix(studioHost, ".sanity.studio")
+}
+
+// initialDatasetChanged reports whether a change of the initial dataset forces
+// a replacement. An imported project has no initial dataset in state, so
+// adding one is an in-place update that only records it.
+func initialDatasetChanged(ctx context.Context, state, _ attr.Value, _ path.Path) (bool, diag.Diagnostics) {
+	var prior types.List
+	diags := tfsdk.ValueAs(ctx, state, &prior)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	return !prior.Null && len(prior.Elems) > 0, diags
+}
+
+// reconcileDatasets brings the inline datasets of the project from the prior
+// to the planned set. Datasets that are not in either set are left alone, so
+// that they can be managed with `sanity_dataset`.
+func (r *ProjectResource) reconcileDatasets(ctx context.Context, projectId string, prior []ProjectResourceDatasetModel, planned []ProjectResourceDatasetModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(prior) == 0 && len(planned) == 0 {
+		return diags
+	}
+
+	datasets, err := r.client.ListDatasets(ctx, projectId)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	existing := make(map[string]string, len(datasets))
+	for _, d := range datasets {
+		existing[d.Name] = d.AclMode
+	}
+
+	want := make(map[string]bool, len(planned))
+	for _, d := range planned {
+		want[d.Name.Value] = true
+	}
+
+	for _, d := range prior {
+		if want[d.Name.Value] {
+			continue
+		}
+		if ctx.Err() != nil {
+			diags.Append(cancelledDiagnostic())
+			return diags
+		}
+		_, err := r.client.Projects.DeleteDataset(ctx, projectId, d.Name.Value)
+		if err != nil && !isNotFound(err) {
+			diags.Append(clientErrorDiagnostic(err))
+			return diags
+		}
+	}
+
+	for _, d := range planned {
+		aclMode, ok := existing[d.Name.Value]
+		switch {
+		case !ok:
+			_, err = r.client.Projects.CreateDataset(ctx, projectId, &sanity.CreateDatasetRequest{
+				Name:    d.Name.Value,
+				AclMode: d.AclMode.Value,
+			})
+		case aclMode != d.AclMode.Value:
+			_, err = r.client.UpdateDataset(ctx, projectId, d.Name.Value, &UpdateDatasetRequest{
+				AclMode: d.AclMode.Value,
+			})
+		default:
+			continue
+		}
+		if ctx.Err() != nil {
+			diags.Append(cancelledDiagnostic())
+			return diags
+		}
+		if err != nil {
+			diags.Append(clientErrorDiagnostic(err))
+			return diags
+		}
+	}
+
+	return diags
+}
+
+// readDatasets refreshes the inline datasets of the project. Datasets that no
+// longer exist are dropped so that they are created again.
+func (r *ProjectResource) readDatasets(ctx context.Context, data *ProjectResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if len(data.Datasets) == 0 {
+		return diags
+	}
+
+	datasets, err := r.client.ListDatasets(ctx, data.Id.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	existing := make(map[string]string, len(datasets))
+	for _, d := range datasets {
+		existing[d.Name] = d.AclMode
+	}
+
+	refreshed := make([]ProjectResourceDatasetModel, 0, len(data.Datasets))
+	for _, d := range data.Datasets {
+		aclMode, ok := existing[d.Name.Value]
+		if !ok {
+			continue
+		}
+		refreshed = append(refreshed, ProjectResourceDatasetModel{
+			Name:    d.Name,
+			AclMode: types.String{Value: aclMode},
+		})
+	}
+	data.Datasets = refreshed
+
+	return diags
+}
+
+// readTimestamps sets when the project was created and last modified. Create
+// and Update use it, because the responses to the requests that change a
+// project do not include when it was last modified.
+func (r *ProjectResource) readTimestamps(ctx context.Context, data *ProjectResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	project, err := r.client.GetProject(ctx, data.Id.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	data.setTimestamps(project)
+
+	return diags
+}
+
+// setTimestamps sets when the project was created and last modified.
+func (m *ProjectResourceModel) setTimestamps(project *Project) {
+	m.CreatedAt = timestampValue(project.CreatedAt)
+	m.UpdatedAt = timestampValue(project.UpdatedAt)
+}
+
+func timestampValue(t time.Time) types.String {
+	if t.IsZero() {
+		return types.String{Value: ""}
+	}
+	return types.String{Value: t.UTC().Format(time.RFC3339)}
+}
+
+// readStudioCORSOrigin sets the ID of the CORS entry that Sanity created for
+// the studio host.
+func (r *ProjectResource) readStudioCORSOrigin(ctx context.Context, data *ProjectResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.StudioCORSOriginId = types.String{Value: ""}
+	if data.StudioHost.Value == "" {
+		return diags
+	}
+
+	entries, err := r.client.ListCORSEntries(ctx, data.Id.Value)
+	if err != nil {
+		diags.Append(clientErrorDiagnostic(err))
+		return diags
+	}
+
+	origin := studioURL(data.StudioHost.Value)
+	for _, entry := range entries {
+		if entry.Origin == origin {
+			data.StudioCORSOriginId = types.String{Value: fmt.Sprintf("%d", entry.Id)}
+			break
+		}
+	}
+
+	return diags
+}
+
+// removeDefaultCORSEntries deletes the CORS entries that Sanity creates along
+// with a new project.
+func (r *ProjectResource) removeDefaultCORSEntries(ctx context.Context, projectId string) error {
+	entries, err := r.client.ListCORSEntries(ctx, projectId)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		_, err = r.client.Projects.DeleteCORSEntry(ctx, projectId, entry.Id)
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newProjectUpdateRequest returns the update that applies the settings of the
+// plan to a new project, or nil if there is nothing to apply.
+func newProjectUpdateRequest(data *ProjectResourceModel) *sanity.UpdateProjectRequest {
 	requiresUpdate := !data.StudioHost.Null ||
 		!data.ExternalStudioHost.Null ||
 		!data.Color.Null ||
 		!data.IsDisabledByUser.Null ||
 		!data.ActivityFeedEnabled.Null
 
-	if requiresUpdate {
-		updateReq := &sanity.UpdateProjectRequest{}
-		if !data.StudioHost.Null {
-			updateReq.StudioHost = data.StudioHost.Value
-		}
-		if !data.ExternalStudioHost.Null {
-			updateReq.ExternalStudioHost = data.ExternalStudioHost.Value
-		}
-		if !data.Color.Null {
-			updateReq.Color = data.Color.Value
+	if !requiresUpdate {
+		return nil
+	}
+
+	updateReq := &sanity.UpdateProjectRequest{}
+	if !data.StudioHost.Null {
+		updateReq.StudioHost = data.StudioHost.Value
+	}
+	if !data.ExternalStudioHost.Null {
+		updateReq.ExternalStudioHost = data.ExternalStudioHost.Value
+	}
+	if !data.Color.Null {
+		updateReq.Color = data.Color.Value
+	}
+	if !data.IsDisabledByUser.Null {
+		updateReq.IsDisabledByUser = sanity.NewBool(data.IsDisabledByUser.Value)
+	}
+	if !data.ActivityFeedEnabled.Null {
+		updateReq.ActivityFeedEnabled = sanity.NewBool(data.ActivityFeedEnabled.Value)
+	}
+
+	return updateReq
+}
+
+// projectReadyTimeout and projectReadyPollInterval bound waitForProject. They
+// are variables so that tests can shorten them.
+var (
+	projectReadyTimeout      = 2 * time.Minute
+	projectReadyPollInterval = 2 * time.Second
+)
+
+// waitForProject polls a newly created project until Sanity returns it. Right
+// after it is created, a project can be missing from the API for a moment, and
+// the CORS and dataset calls that follow fail until it shows up.
+func (r *ProjectResource) waitForProject(ctx context.Context, projectId string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	waitCtx, cancel := context.WithTimeout(ctx, projectReadyTimeout)
+	defer cancel()
+
+	for {
+		_, err := r.client.Projects.Get(waitCtx, projectId)
+		if err == nil {
+			return diags
 		}
-		if !data.IsDisabledByUser.Null {
-			updateReq.IsDisabledByUser = sanity.NewBool(data.IsDisabledByUser.Value)
+		if ctx.Err() != nil {
+			diags.Append(cancelledDiagnostic())
+			return diags
 		}
-		if !data.ActivityFeedEnabled.Null {
-			updateReq.ActivityFeedEnabled = sanity.NewBool(data.ActivityFeedEnabled.Value)
+		if !isNotFound(err) && waitCtx.Err() == nil {
+			diags.Append(clientErrorDiagnostic(err))
+			return diags
 		}
-		project, err = r.client.Projects.Update(ctx, project.Id, updateReq)
-		if err != nil {
-			resp.Diagnostics.AddError("Client Error", err.Error())
-			r.client.Projects.Delete(ctx, project.Id)
-			return
+
+		tflog.Debug(ctx, "sanity project is not ready yet", map[string]interface{}{"id": projectId})
+
+		select {
+		case <-ctx.Done():
+			diags.Append(cancelledDiagnostic())
+			return diags
+		case <-waitCtx.Done():
+			diags.AddError(
+				"Project Not Ready",
+				fmt.Sprintf("Project %s was created but was not ready after %s.", projectId, projectReadyTimeout),
+			)
+			return diags
+		case <-time.After(projectReadyPollInterval):
 		}
 	}
+}
 
-	data.Id = types.String{Value: project.Id}
-	data.Name = types.String{Value: project.DisplayName}
-	data.Organization = types.String{Value: project.OrganizationId}
-	data.StudioHost = types.String{Value: project.StudioHost}
-	data.ExternalStudioHost = types.String{Value: project.Metadata["externalStudioHost"]}
-	data.Color = types.String{Value: project.Metadata["color"]}
-	data.IsDisabledByUser = types.Bool{Value: project.IsDisabledByUser}
-	data.ActivityFeedEnabled = types.Bool{Value: project.ActivityFeedEnabled}
+// rollbackCreate deletes a project whose creation failed part of the way
+// through. A project that is already gone counts as rolled back.
+func (r *ProjectResource) rollbackCreate(ctx context.Context, projectId string, diags *diag.Diagnostics) {
+	// a cancelled context cannot send the delete request, so the user is told
+	// about the project instead
+	if ctx.Err() != nil {
+		diags.AddError(
+			"Rollback Skipped",
+			fmt.Sprintf("Project %s was created but its creation was cancelled before it finished. Delete the project at https://sanity.io/manage or import it.", projectId),
+		)
+		return
+	}
 
-	tflog.Trace(ctx, "created a sanity project", map[string]interface{}{"id": project.Id, "name": project.DisplayName})
+	_, err := r.client.Projects.Delete(ctx, projectId)
+	if err != nil && !isNotFound(err) {
+		diags.AddError(
+			"Rollback Failed",
+			fmt.Sprintf("Project %s was created but could not be deleted after a later step failed. Delete the project at https://sanity.io/manage or import it, got error: %s", projectId, err),
+		)
+		return
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	tflog.Warn(ctx, "deleted a partially created sanity project", map[string]interface{}{"id": projectId})
 }
 
 func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -229,30 +885,39 @@ func (r *ProjectResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity project", map[string]interface{}{"id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Project id is null", "Project id is null")
 		return
 	}
 
-	project, err := r.client.Projects.Get(ctx, data.Id.Value)
+	project, err := r.client.GetProject(ctx, data.Id.Value)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
-	data.Id = types.String{Value: project.Id}
-	data.Name = types.String{Value: project.DisplayName}
-	data.Organization = types.String{Value: project.OrganizationId}
-	data.StudioHost = types.String{Value: project.StudioHost}
-	data.ExternalStudioHost = types.String{Value: project.Metadata["externalStudioHost"]}
-	data.Color = types.String{Value: project.Metadata["color"]}
-	data.IsDisabledByUser = types.Bool{Value: project.IsDisabledByUser}
-	data.ActivityFeedEnabled = types.Bool{Value: project.ActivityFeedEnabled}
+	data.fromSanity(&project.Project)
+	data.setTimestamps(project)
+	data.setNullDefaults()
 
+	resp.Diagnostics.Append(r.readDatasets(ctx, data)...)
+	resp.Diagnostics.Append(r.readStudioCORSOrigin(ctx, data)...)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *ProjectResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -261,64 +926,194 @@ func (r *ProjectResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity project", map[string]interface{}{"id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Project id is null", "Project id is null")
 		return
 	}
 
+	var organization string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("organization"), &organization)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Organization.Null && !data.Organization.Unknown && data.Organization.Value != organization {
+		if data.Organization.Value == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("organization"),
+				"Invalid Organization",
+				fmt.Sprintf("Project %s belongs to organization %s and cannot be turned into a personal project. Transfer it to another organization instead.", data.Id.Value, organization),
+			)
+			return
+		}
+
+		if !r.transfer(ctx, data, resp) {
+			return
+		}
+
+		// keep the transfer in state even if the rest of the update fails
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("organization"), data.Organization)...)
+	}
+
+	var datasets []ProjectResourceDatasetModel
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("dataset"), &datasets)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.reconcileDatasets(ctx, data.Id.Value, datasets, data.Datasets)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	var studioHost string
 	req.State.GetAttribute(ctx, path.Root("studio_host"), &studioHost)
 
-	requiresUpdate := !data.Name.Null ||
-		(!data.StudioHost.Null && studioHost == "") ||
-		!data.ExternalStudioHost.Null ||
-		!data.Color.Null ||
-		!data.IsDisabledByUser.Null ||
-		!data.ActivityFeedEnabled.Null
+	// the studio host may only be set once, and the plan modifier forces a
+	// replacement for any other change, so it is only sent when it changed
+	studioHostChanged := !data.StudioHost.Null && data.StudioHost.Value != studioHost
 
-	if !requiresUpdate {
-		return
+	var externalStudioHost, color types.String
+	req.State.GetAttribute(ctx, path.Root("external_studio_host"), &externalStudioHost)
+	req.State.GetAttribute(ctx, path.Root("color"), &color)
+
+	var metadata types.Map
+	req.State.GetAttribute(ctx, path.Root("metadata"), &metadata)
+
+	// go-sanity cannot set custom metadata or clear metadata, so the custom
+	// entries and the entries that were removed from the configuration are
+	// sent with a separate request
+	metadataUpdate := metadataChanges(metadata, data.Metadata)
+	if data.ExternalStudioHost.Null && externalStudioHost.Value != "" {
+		// go-sanity sets `externalHost` while Sanity reads
+		// `externalStudioHost`, so both are cleared
+		metadataUpdate["externalHost"] = ""
+		metadataUpdate["externalStudioHost"] = ""
+	}
+	if data.Color.Null && color.Value != "" {
+		metadataUpdate["color"] = ""
 	}
 
-	updateReq := &sanity.UpdateProjectRequest{}
-	if !data.Name.Null {
-		updateReq.DisplayName = data.Name.Value
+	var name types.String
+	var isDisabledByUser, activityFeedEnabled types.Bool
+	req.State.GetAttribute(ctx, path.Root("name"), &name)
+	req.State.GetAttribute(ctx, path.Root("disabled_by_user"), &isDisabledByUser)
+	req.State.GetAttribute(ctx, path.Root("activity_feed_enabled"), &activityFeedEnabled)
+
+	// the project itself is only updated when one of its settings changed, so
+	// that a change to the metadata alone is a single request
+	requiresUpdate := !data.Name.Equal(name) ||
+		studioHostChanged ||
+		(!data.ExternalStudioHost.Null && !data.ExternalStudioHost.Equal(externalStudioHost)) ||
+		(!data.Color.Null && !strings.EqualFold(data.Color.Value, color.Value)) ||
+		(!data.IsDisabledByUser.Null && !data.IsDisabledByUser.Equal(isDisabledByUser)) ||
+		(!data.ActivityFeedEnabled.Null && !data.ActivityFeedEnabled.Equal(activityFeedEnabled))
+
+	if !requiresUpdate && len(metadataUpdate) == 0 {
+		data.StudioURL = types.String{Value: studioURL(data.StudioHost.Value)}
+		req.State.GetAttribute(ctx, path.Root("all_metadata"), &data.AllMetadata)
+		resp.Diagnostics.Append(r.readTimestamps(ctx, data)...)
+		resp.Diagnostics.Append(r.readStudioCORSOrigin(ctx, data)...)
+		resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+		return
 	}
-	if studioHost == "" && !data.StudioHost.Null {
-		updateReq.StudioHost = data.StudioHost.Value
+
+	var project *sanity.Project
+	var err error
+	if requiresUpdate {
+		updateReq := &sanity.UpdateProjectRequest{}
+		if !data.Name.Null {
+			updateReq.DisplayName = data.Name.Value
+		}
+		if studioHostChanged {
+			updateReq.StudioHost = data.StudioHost.Value
+		}
+		if !data.ExternalStudioHost.Null {
+			updateReq.ExternalStudioHost = data.ExternalStudioHost.Value
+		}
+		if !data.Color.Null {
+			updateReq.Color = data.Color.Value
+		}
+		if !data.IsDisabledByUser.Null {
+			updateReq.IsDisabledByUser = sanity.NewBool(data.IsDisabledByUser.Value)
+		}
+		if !data.ActivityFeedEnabled.Null {
+			updateReq.ActivityFeedEnabled = sanity.NewBool(data.ActivityFeedEnabled.Value)
+		}
+		project, err = r.client.Projects.Update(ctx, data.Id.Value, updateReq)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
 	}
-	if !data.ExternalStudioHost.Null {
-		updateReq.ExternalStudioHost = data.ExternalStudioHost.Value
+
+	if len(metadataUpdate) > 0 {
+		project, err = r.client.UpdateProjectMetadata(ctx, data.Id.Value, metadataUpdate)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
 	}
-	if !data.Color.Null {
-		updateReq.Color = data.Color.Value
+
+	data.fromSanity(project)
+
+	resp.Diagnostics.Append(r.readTimestamps(ctx, data)...)
+	resp.Diagnostics.Append(r.readStudioCORSOrigin(ctx, data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// transfer moves the project to the organization in the plan. It reports
+// whether the transfer succeeded.
+func (r *ProjectResource) transfer(ctx context.Context, data *ProjectResourceModel, resp *resource.UpdateResponse) bool {
+	organizations, err := r.client.ListOrganizations(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return false
 	}
-	if !data.IsDisabledByUser.Null {
-		updateReq.IsDisabledByUser = sanity.NewBool(data.IsDisabledByUser.Value)
+
+	found := false
+	for _, org := range organizations {
+		if org.Id == data.Organization.Value {
+			found = true
+			break
+		}
 	}
-	if !data.ActivityFeedEnabled.Null {
-		updateReq.ActivityFeedEnabled = sanity.NewBool(data.ActivityFeedEnabled.Value)
+
+	if !found {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("organization"),
+			"Invalid Organization",
+			fmt.Sprintf("Project %s cannot be transferred to organization %q because it does not exist or you are not a member of it", data.Id.Value, data.Organization.Value),
+		)
+		return false
 	}
-	project, err := r.client.Projects.Update(ctx, data.Id.Value, updateReq)
+
+	project, err := r.client.TransferProject(ctx, data.Id.Value, data.Organization.Value)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
-		r.client.Projects.Delete(ctx, project.Id)
-		return
+		d := clientErrorDiagnosticf(err, "project %s could not be transferred to organization %s", data.Id.Value, data.Organization.Value)
+		resp.Diagnostics.AddAttributeError(path.Root("organization"), d.Summary(), d.Detail())
+		return false
 	}
 
-	data.Id = types.String{Value: project.Id}
-	data.Name = types.String{Value: project.DisplayName}
 	data.Organization = types.String{Value: project.OrganizationId}
-	data.StudioHost = types.String{Value: project.StudioHost}
-	data.ExternalStudioHost = types.String{Value: project.Metadata["externalStudioHost"]}
-	data.Color = types.String{Value: project.Metadata["color"]}
-	data.IsDisabledByUser = types.Bool{Value: project.IsDisabledByUser}
-	data.ActivityFeedEnabled = types.Bool{Value: project.ActivityFeedEnabled}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	tflog.Trace(ctx, "transferred a sanity project", map[string]interface{}{"id": data.Id.Value, "organization": project.OrganizationId})
+
+	return true
 }
 
 func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *ProjectResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -327,19 +1122,148 @@ func (r *ProjectResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity project", map[string]interface{}{"id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Project id is null", "Project id is null")
 		return
 	}
 
+	if data.DeletionProtection.Value {
+		resp.Diagnostics.AddError(
+			"Project is protected from deletion",
+			fmt.Sprintf("Project %s has deletion_protection enabled. Set deletion_protection to false and apply the change before deleting the project.", data.Id.Value),
+		)
+		return
+	}
+
+	if data.DeleteBehavior.Value == projectDeleteBehaviorArchive {
+		_, err := r.client.Projects.Update(ctx, data.Id.Value, &sanity.UpdateProjectRequest{
+			IsDisabledByUser: sanity.NewBool(true),
+		})
+
+		if err != nil && !isNotFound(err) {
+			resp.Diagnostics.Append(clientErrorDiagnosticf(err, "project %s could not be archived", data.Id.Value))
+			return
+		}
+
+		tflog.Info(ctx, "archived sanity project instead of deleting it", map[string]interface{}{"id": data.Id.Value})
+		return
+	}
+
 	_, err := r.client.Projects.Delete(ctx, data.Id.Value)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("project %s could not be deleted, got error: %s", data.Id.Value, err))
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "project %s could not be deleted", data.Id.Value))
 		return
 	}
 }
 
 func (r *ProjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if !projectIdRegexp.MatchString(req.ID) {
+		resp.Diagnostics.AddError(
+			"Import Error",
+			fmt.Sprintf("%q is not a valid project ID. The format for importing a project is project-id, where the ID contains only lowercase letters and numbers, as shown at the top of the project page in Sanity. Datasets, CORS origins, and other project resources are imported separately.", req.ID),
+		)
+		return
+	}
+
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("delete_behavior"), projectDeleteBehaviorDelete)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("manage_default_cors"), true)...)
+}
+
+// projectResourceModelV0 is the model of version 0 of the schema.
+type projectResourceModelV0 struct {
+	Id                  types.String `tfsdk:"id"`
+	Name                types.String `tfsdk:"name"`
+	Organization        types.String `tfsdk:"organization"`
+	StudioHost          types.String `tfsdk:"studio_host"`
+	StudioURL           types.String `tfsdk:"studio_url"`
+	ExternalStudioHost  types.String `tfsdk:"external_studio_host"`
+	Color               types.String `tfsdk:"color"`
+	IsDisabledByUser    types.Bool   `tfsdk:"disabled_by_user"`
+	ActivityFeedEnabled types.Bool   `tfsdk:"activity_feed_enabled"`
+	Features            types.Set    `tfsdk:"features"`
+	ManageDefaultCORS   types.Bool   `tfsdk:"manage_default_cors"`
+	DeletionProtection  types.Bool   `tfsdk:"deletion_protection"`
+	StudioCORSOriginId  types.String `tfsdk:"studio_cors_origin_id"`
+	Token               types.String `tfsdk:"token"`
+
+	Datasets []ProjectResourceDatasetModel `tfsdk:"dataset"`
+}
+
+func (r *ProjectResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 has no metadata, all_metadata, created_at, updated_at,
+		// delete_behavior, or initial_dataset. The computed attributes are set
+		// by the next read, and the others get their defaults.
+		0: {
+			PriorSchema: &tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"token":                 {Type: types.StringType, Optional: true, Sensitive: true},
+					"id":                    {Type: types.StringType, Computed: true},
+					"name":                  {Type: types.StringType, Optional: true, Computed: true},
+					"organization":          {Type: types.StringType, Optional: true, Computed: true},
+					"studio_host":           {Type: types.StringType, Optional: true, Computed: true},
+					"studio_url":            {Type: types.StringType, Computed: true},
+					"studio_cors_origin_id": {Type: types.StringType, Computed: true},
+					"external_studio_host":  {Type: types.StringType, Optional: true},
+					"color":                 {Type: types.StringType, Optional: true},
+					"disabled_by_user":      {Type: types.BoolType, Optional: true, Computed: true},
+					"activity_feed_enabled": {Type: types.BoolType, Optional: true, Computed: true},
+					"features":              {Type: types.SetType{ElemType: types.StringType}, Computed: true},
+					"manage_default_cors":   {Type: types.BoolType, Optional: true, Computed: true},
+					"deletion_protection":   {Type: types.BoolType, Optional: true, Computed: true},
+				},
+				Blocks: map[string]tfsdk.Block{
+					"dataset": {
+						NestingMode: tfsdk.BlockNestingModeSet,
+						Attributes: map[string]tfsdk.Attribute{
+							"name":     {Type: types.StringType, Required: true},
+							"acl_mode": {Type: types.StringType, Required: true},
+						},
+					},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior projectResourceModelV0
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data := ProjectResourceModel{
+					Id:                  prior.Id,
+					Name:                prior.Name,
+					Organization:        prior.Organization,
+					StudioHost:          prior.StudioHost,
+					StudioURL:           prior.StudioURL,
+					ExternalStudioHost:  prior.ExternalStudioHost,
+					Color:               prior.Color,
+					Metadata:            types.Map{ElemType: types.StringType, Null: true},
+					AllMetadata:         types.Map{ElemType: types.StringType, Null: true},
+					IsDisabledByUser:    prior.IsDisabledByUser,
+					ActivityFeedEnabled: prior.ActivityFeedEnabled,
+					Features:            prior.Features,
+					CreatedAt:           types.String{Null: true},
+					UpdatedAt:           types.String{Null: true},
+					ManageDefaultCORS:   prior.ManageDefaultCORS,
+					DeletionProtection:  prior.DeletionProtection,
+					DeleteBehavior:      types.String{Null: true},
+					StudioCORSOriginId:  prior.StudioCORSOriginId,
+					Token:               prior.Token,
+					Datasets:            append([]ProjectResourceDatasetModel{}, prior.Datasets...),
+					InitialDataset:      []ProjectResourceDatasetModel{},
+				}
+				data.setNullDefaults()
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
 }
@@ -0,0 +1,1405 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+func TestProjectResource(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "create, update and delete",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "id", "p1"),
+							resource.TestCheckResourceAttr("sanity_project.test", "name", "Test"),
+							resource.TestCheckResourceAttr("sanity_project.test", "studio_host", ""),
+							resource.TestCheckResourceAttr("sanity_project.test", "disabled_by_user", "false"),
+							resource.TestCheckResourceAttr("sanity_project.test", "activity_feed_enabled", "true"),
+							resource.TestCheckResourceAttr("sanity_project.test", "created_at", "2022-10-01T12:00:00Z"),
+							testCheckMock(func() error {
+								if p := m.project("p1"); p == nil || p.DisplayName != "Test" {
+									return fmt.Errorf("expected project p1 named Test, got %+v", p)
+								}
+								return nil
+							}),
+						),
+					},
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name                  = "Renamed"
+  color                 = "#AABBCC"
+  activity_feed_enabled = false
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "id", "p1"),
+							resource.TestCheckResourceAttr("sanity_project.test", "name", "Renamed"),
+							resource.TestCheckResourceAttr("sanity_project.test", "color", "#AABBCC"),
+							resource.TestCheckResourceAttr("sanity_project.test", "activity_feed_enabled", "false"),
+							resource.TestCheckResourceAttr("sanity_project.test", "updated_at", "2022-10-01T12:02:00Z"),
+							testCheckMock(func() error {
+								p := m.project("p1")
+								if p.DisplayName != "Renamed" || p.Metadata["color"] != "#aabbcc" || p.ActivityFeedEnabled {
+									return fmt.Errorf("project p1 was not updated: %+v", p)
+								}
+								return nil
+							}),
+						),
+					},
+					{
+						// removing the color resets the project to the default color
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name                  = "Renamed"
+  activity_feed_enabled = false
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckNoResourceAttr("sanity_project.test", "color"),
+							resource.TestCheckNoResourceAttr("sanity_project.test", "all_metadata.color"),
+							testCheckMock(func() error {
+								if color, ok := m.project("p1").Metadata["color"]; ok {
+									return fmt.Errorf("expected the color of project p1 to be cleared, got %q", color)
+								}
+								return nil
+							}),
+						),
+					},
+				}
+			},
+			checkDestroy: func(m *mockSanity) error {
+				if m.project("p1") != nil {
+					return fmt.Errorf("project p1 was not deleted")
+				}
+				return nil
+			},
+		},
+		{
+			name: "archived on destroy",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name            = "Test"
+  delete_behavior = "archive"
+}
+`),
+					Check: resource.TestCheckResourceAttr("sanity_project.test", "delete_behavior", "archive"),
+				}}
+			},
+			checkDestroy: func(m *mockSanity) error {
+				p := m.project("p1")
+				if p == nil {
+					return fmt.Errorf("expected project p1 to be archived, but it was deleted")
+				}
+				if !p.IsDisabledByUser {
+					return fmt.Errorf("expected project p1 to be disabled")
+				}
+				return testCheckRequestCount(m, "DELETE", "/projects/p1", 0)(nil)
+			},
+		},
+		{
+			name: "default CORS entries are removed",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name        = "Test"
+  studio_host = "test"
+}
+`),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_project.test", "studio_host", "test"),
+						resource.TestCheckResourceAttr("sanity_project.test", "studio_url", "https://test.sanity.studio"),
+						resource.TestCheckResourceAttrSet("sanity_project.test", "studio_cors_origin_id"),
+						testCheckMock(func() error {
+							var origins []string
+							m.withProject("p1", func(p *mockProject) {
+								for _, e := range p.cors {
+									origins = append(origins, e.Origin)
+								}
+							})
+							if len(origins) != 1 || origins[0] != "https://test.sanity.studio" {
+								return fmt.Errorf("expected only the studio CORS origin, got %v", origins)
+							}
+							return nil
+						}),
+					),
+				}}
+			},
+		},
+		{
+			name: "default CORS entries are kept",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name                = "Test"
+  studio_host         = "test"
+  manage_default_cors = false
+}
+`),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_project.test", "manage_default_cors", "false"),
+						testCheckRequestCount(m, "DELETE", "/projects/p1/cors/.*", 0),
+						testCheckMock(func() error {
+							var origins []string
+							m.withProject("p1", func(p *mockProject) {
+								for _, e := range p.cors {
+									origins = append(origins, e.Origin)
+								}
+							})
+							if len(origins) != 2 || origins[0] != "http://localhost:3333" || origins[1] != "https://test.sanity.studio" {
+								return fmt.Errorf("expected the default and studio CORS origins, got %v", origins)
+							}
+							return nil
+						}),
+					),
+				}}
+			},
+		},
+		{
+			name: "studio_host can be set once",
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := func(studioHost string) string {
+					return m.providerConfig(fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name        = "Test"
+  studio_host = %q
+}
+`, studioHost))
+				}
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+					},
+					{
+						// setting it the first time updates the project
+						Config: config("first"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "id", "p1"),
+							resource.TestCheckResourceAttr("sanity_project.test", "studio_host", "first"),
+						),
+					},
+					{
+						Config:   config("first"),
+						PlanOnly: true,
+					},
+					{
+						// changing it replaces the project
+						Config: config("second"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttrWith("sanity_project.test", "id", func(id string) error {
+								if id == "p1" {
+									return fmt.Errorf("expected a new project")
+								}
+								return nil
+							}),
+							resource.TestCheckResourceAttr("sanity_project.test", "studio_host", "second"),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "a failed update leaves the project intact",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+					},
+					{
+						PreConfig: func() {
+							m.fail("PATCH", "/projects/p1", 500)
+						},
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Renamed"
+}
+`),
+						ExpectError: regexp.MustCompile(`Sanity API Error: 500`),
+					},
+					{
+						PreConfig: func() {
+							m.clearFailures()
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "id", "p1"),
+							resource.TestCheckResourceAttr("sanity_project.test", "name", "Test"),
+							testCheckRequestCount(m, "DELETE", "/projects/p1", 0),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "studio_host must be a subdomain prefix",
+			steps: func(m *mockSanity) []resource.TestStep {
+				var steps []resource.TestStep
+				for _, studioHost := range []string{"https://test.sanity.studio", "test.sanity.studio", "Test", "-test"} {
+					steps = append(steps, resource.TestStep{
+						Config: m.providerConfig(fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name        = "Test"
+  studio_host = %q
+}
+`, studioHost)),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(`The studio host must be only the subdomain prefix`),
+					})
+				}
+				return steps
+			},
+		},
+		{
+			name: "a studio host reported as a URL is read as its prefix",
+			setup: func(m *mockSanity) {
+				p := m.addProject("p1", "Test")
+				p.project.StudioHost = "https://test.sanity.studio"
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name        = "Test"
+  studio_host = "test"
+}
+`),
+					ResourceName:  "sanity_project.test",
+					ImportState:   true,
+					ImportStateId: "p1",
+					ImportStateCheck: func(states []*terraform.InstanceState) error {
+						if got := states[0].Attributes["studio_host"]; got != "test" {
+							return fmt.Errorf("expected studio_host test, got %q", got)
+						}
+						return nil
+					},
+				}}
+			},
+		},
+		{
+			name: "external_studio_host can be set and cleared",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name                 = "Test"
+  external_studio_host = "https://studio.example.com"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "external_studio_host", "https://studio.example.com"),
+							testCheckMock(func() error {
+								if h := m.project("p1").Metadata["externalStudioHost"]; h != "https://studio.example.com" {
+									return fmt.Errorf("expected the external studio host to be set, got %q", h)
+								}
+								return nil
+							}),
+						),
+					},
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckNoResourceAttr("sanity_project.test", "external_studio_host"),
+							testCheckMock(func() error {
+								metadata := m.project("p1").Metadata
+								if _, ok := metadata["externalStudioHost"]; ok {
+									return fmt.Errorf("expected externalStudioHost to be cleared, got %v", metadata)
+								}
+								if _, ok := metadata["externalHost"]; ok {
+									return fmt.Errorf("expected externalHost to be cleared, got %v", metadata)
+								}
+								return nil
+							}),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "externally deleted",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+					},
+					{
+						PreConfig: func() {
+							m.deleteProject("p1")
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+					},
+				}
+			},
+		},
+		{
+			name: "color validation",
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := func(color string) string {
+					return m.providerConfig(fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name  = "Test"
+  color = %q
+}
+`, color))
+				}
+
+				var steps []resource.TestStep
+				for _, color := range []string{"#aabbcc", "#AABBCC", "#0a1B2c"} {
+					steps = append(steps, resource.TestStep{
+						Config:             config(color),
+						PlanOnly:           true,
+						ExpectNonEmptyPlan: true,
+					})
+				}
+				for _, color := range []string{"#gggggg", "aabbcc", "#abc", "#aabbccdd", " #aabbcc", ""} {
+					steps = append(steps, resource.TestStep{
+						Config:      config(color),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(`The color must be a hex value in the form #rrggbb`),
+					})
+				}
+				return steps
+			},
+		},
+		{
+			name: "import",
+			setup: func(m *mockSanity) {
+				p := m.addProject("imported", "Imported")
+				p.project.Metadata["color"] = "#123456"
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name  = "Imported"
+  color = "#123456"
+}
+`),
+					ResourceName:  "sanity_project.test",
+					ImportState:   true,
+					ImportStateId: "imported",
+					ImportStateCheck: func(states []*terraform.InstanceState) error {
+						if len(states) != 1 {
+							return fmt.Errorf("expected 1 imported resource, got %d", len(states))
+						}
+						attrs := states[0].Attributes
+						if attrs["name"] != "Imported" || attrs["color"] != "#123456" || attrs["delete_behavior"] != "delete" ||
+							attrs["deletion_protection"] != "false" || attrs["manage_default_cors"] != "true" {
+							return fmt.Errorf("unexpected imported attributes: %v", attrs)
+						}
+						return nil
+					},
+				}}
+			},
+		},
+	})
+}
+
+func TestProjectResource_deletionProtection(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+
+	rt := newResourceTest(t, m, NewProjectResource())
+	state := rt.stateWith(map[string]attr.Value{
+		"id":                  types.String{Value: "p1"},
+		"deletion_protection": types.Bool{Value: true},
+	})
+
+	requireErrorDiagnostic(t, rt.delete(state), "Project is protected from deletion")
+	if n := len(m.requestsTo("DELETE", "/projects/p1")); n != 0 {
+		t.Fatalf("expected no delete request, got %d", n)
+	}
+
+	// the protection also applies to archiving
+	state = rt.stateWith(map[string]attr.Value{
+		"id":                  types.String{Value: "p1"},
+		"deletion_protection": types.Bool{Value: true},
+		"delete_behavior":     types.String{Value: projectDeleteBehaviorArchive},
+	})
+	requireErrorDiagnostic(t, rt.delete(state), "Project is protected from deletion")
+	if n := len(m.requestsTo("PATCH", "/projects/p1")); n != 0 {
+		t.Fatalf("expected no update request, got %d", n)
+	}
+	if m.project("p1") == nil {
+		t.Fatal("expected the project to be kept")
+	}
+}
+
+func TestProjectResource_features(t *testing.T) {
+	config := `
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "the enabled features are read",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config),
+						Check:  resource.TestCheckResourceAttr("sanity_project.test", "features.#", "0"),
+					},
+					{
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.project.Features = []string{"privateDataset", "thirdPartyLogin"}
+							})
+						},
+						RefreshState: true,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "features.#", "2"),
+							resource.TestCheckTypeSetElemAttr("sanity_project.test", "features.*", "privateDataset"),
+							resource.TestCheckTypeSetElemAttr("sanity_project.test", "features.*", "thirdPartyLogin"),
+						),
+					},
+					{
+						Config:   m.providerConfig(config),
+						PlanOnly: true,
+					},
+				}
+			},
+		},
+		{
+			name: "features cannot be configured",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name     = "Test"
+  features = ["privateDataset"]
+}
+`),
+					ExpectError: regexp.MustCompile(`Invalid Configuration for Read-Only Attribute`),
+				}}
+			},
+		},
+	})
+}
+
+func TestProjectResource_upgradeFromV0(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+	m.withProject("p1", func(p *mockProject) {
+		p.datasets = []sanity.Dataset{{Name: "production", AclMode: "public"}}
+	})
+
+	rt := newResourceTest(t, m, NewProjectResource())
+	state, diags := rt.upgradeState(0, `{
+  "id": "p1",
+  "name": "Test",
+  "organization": "org1",
+  "studio_host": "",
+  "studio_url": "",
+  "studio_cors_origin_id": null,
+  "external_studio_host": null,
+  "color": null,
+  "disabled_by_user": false,
+  "activity_feed_enabled": false,
+  "features": [],
+  "manage_default_cors": null,
+  "deletion_protection": true,
+  "token": null,
+  "dataset": [{"name": "production", "acl_mode": "public"}]
+}`)
+	requireNoDiagnostics(t, diags)
+
+	data := stateModel[ProjectResourceModel](t, state)
+	if data.Id.Value != "p1" || data.Name.Value != "Test" || len(data.Datasets) != 1 || data.Datasets[0].Name.Value != "production" {
+		t.Fatalf("expected the version 0 attributes to be kept, got %+v", data)
+	}
+	if !data.DeletionProtection.Value || !data.ManageDefaultCORS.Value || data.DeleteBehavior.Value != projectDeleteBehaviorDelete || !data.Metadata.Null {
+		t.Fatalf("expected the new attributes to get their defaults, got %+v", data)
+	}
+
+	state, diags = rt.read(state)
+	requireNoDiagnostics(t, diags)
+
+	data = stateModel[ProjectResourceModel](t, state)
+	if data.CreatedAt.Null || data.UpdatedAt.Null || data.AllMetadata.Null {
+		t.Fatalf("expected the read to set the new computed attributes, got %+v", data)
+	}
+	if len(data.Datasets) != 1 || len(data.InitialDataset) != 0 || !data.DeletionProtection.Value {
+		t.Fatalf("expected the read to keep the upgraded state, got %+v", data)
+	}
+}
+
+func TestProjectResource_readIsOneRequest(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+
+	rt := newResourceTest(t, m, NewProjectResource())
+	state, diags := rt.read(rt.stateWith(map[string]attr.Value{
+		"id": types.String{Value: "p1"},
+	}))
+	requireNoDiagnostics(t, diags)
+
+	data := stateModel[ProjectResourceModel](t, state)
+	if data.CreatedAt.Value != "2022-10-01T12:00:00Z" || data.UpdatedAt.Value != "2022-10-01T12:00:00Z" {
+		t.Fatalf("expected the timestamps of the project, got %s and %s", data.CreatedAt, data.UpdatedAt)
+	}
+	if n := len(m.requestsTo("GET", "/projects/p1")); n != 1 {
+		t.Fatalf("expected the project to be fetched once, got %d requests", n)
+	}
+}
+
+// TestProjectResource_sameState checks that Create, Read and Update, which all
+// copy the project into state with fromSanity, produce the same state.
+func TestProjectResource_sameState(t *testing.T) {
+	config := func(name string) string {
+		return fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name                  = %q
+  studio_host           = "test-studio"
+  color                 = "#AABBCC"
+  activity_feed_enabled = false
+  metadata = {
+    team = "web"
+  }
+}
+`, name)
+	}
+
+	// capture keeps the attributes of the project
+	capture := func(attributes *map[string]string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			*attributes = s.RootModule().Resources["sanity_project.test"].Primary.Attributes
+			return nil
+		}
+	}
+
+	// compare checks that the attributes of the project are the same as the
+	// captured ones, except for the given attributes that must differ
+	compare := func(attributes *map[string]string, changed ...string) resource.TestCheckFunc {
+		return func(s *terraform.State) error {
+			got := s.RootModule().Resources["sanity_project.test"].Primary.Attributes
+			want := *attributes
+
+			isChanged := make(map[string]bool)
+			for _, name := range changed {
+				if got[name] == want[name] {
+					return fmt.Errorf("expected %s to change from %q", name, want[name])
+				}
+				isChanged[name] = true
+			}
+			for name, value := range want {
+				if !isChanged[name] && got[name] != value {
+					return fmt.Errorf("expected %s to be %q, got %q", name, value, got[name])
+				}
+			}
+			if len(got) != len(want) {
+				return fmt.Errorf("expected attributes %v, got %v", want, got)
+			}
+			return nil
+		}
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "create, read and update",
+			steps: func(m *mockSanity) []resource.TestStep {
+				var created, updated map[string]string
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config("Test")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "color", "#AABBCC"),
+							resource.TestCheckResourceAttr("sanity_project.test", "all_metadata.team", "web"),
+							capture(&created),
+						),
+					},
+					{
+						RefreshState: true,
+						Check:        compare(&created),
+					},
+					{
+						Config: m.providerConfig(config("Renamed")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							compare(&created, "name", "updated_at"),
+							capture(&updated),
+						),
+					},
+					{
+						RefreshState: true,
+						Check:        compare(&updated),
+					},
+				}
+			},
+		},
+	})
+}
+
+// TestProjectResource_nullDefaults reads state written before the attributes
+// with defaults were added to the schema.
+func TestProjectResource_nullDefaults(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+
+	rt := newResourceTest(t, m, NewProjectResource())
+	state, diags := rt.read(rt.stateWith(map[string]attr.Value{
+		"id":   types.String{Value: "p1"},
+		"name": types.String{Value: "Test"},
+	}))
+	requireNoDiagnostics(t, diags)
+
+	data := stateModel[ProjectResourceModel](t, state)
+	if !data.ManageDefaultCORS.Value || data.ManageDefaultCORS.Null {
+		t.Errorf("expected manage_default_cors to default to true, got %v", data.ManageDefaultCORS)
+	}
+	if data.DeletionProtection.Value || data.DeletionProtection.Null {
+		t.Errorf("expected deletion_protection to default to false, got %v", data.DeletionProtection)
+	}
+	if data.DeleteBehavior.Value != projectDeleteBehaviorDelete {
+		t.Errorf("expected delete_behavior to default to delete, got %v", data.DeleteBehavior)
+	}
+}
+
+func TestProjectResource_deletionProtectionFromEnv(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "SANITY_DELETION_PROTECTION is the default",
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`)
+				return []resource.TestStep{
+					{
+						PreConfig: func() {
+							t.Setenv("SANITY_DELETION_PROTECTION", "true")
+						},
+						Config: config,
+						Check:  resource.TestCheckResourceAttr("sanity_project.test", "deletion_protection", "true"),
+					},
+					{
+						PreConfig: func() {
+							t.Setenv("SANITY_DELETION_PROTECTION", "invalid")
+						},
+						Config:      config,
+						ExpectError: regexp.MustCompile(`SANITY_DELETION_PROTECTION environment variable must be a\s+boolean`),
+					},
+					{
+						// unsetting the variable falls back to false, which
+						// allows the project to be destroyed
+						PreConfig: func() {
+							t.Setenv("SANITY_DELETION_PROTECTION", "")
+						},
+						Config: config,
+						Check:  resource.TestCheckResourceAttr("sanity_project.test", "deletion_protection", "false"),
+					},
+				}
+			},
+		},
+		{
+			name: "the configuration takes precedence",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					PreConfig: func() {
+						t.Setenv("SANITY_DELETION_PROTECTION", "true")
+					},
+					Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name                = "Test"
+  deletion_protection = false
+}
+`),
+					Check: resource.TestCheckResourceAttr("sanity_project.test", "deletion_protection", "false"),
+				}}
+			},
+		},
+	})
+}
+
+func TestProjectResource_initialDataset(t *testing.T) {
+	config := func(aclMode string) string {
+		return fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name = "Test"
+
+  initial_dataset {
+    name     = "production"
+    acl_mode = %q
+  }
+}
+`, aclMode)
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "created with the project",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(config("private")),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_project.test", "initial_dataset.0.name", "production"),
+						testCheckMock(func() error {
+							var datasets []sanity.Dataset
+							m.withProject("p1", func(p *mockProject) {
+								datasets = append(datasets, p.datasets...)
+							})
+							if len(datasets) != 1 || datasets[0].Name != "production" || datasets[0].AclMode != "private" {
+								return fmt.Errorf("expected a private production dataset, got %+v", datasets)
+							}
+							return nil
+						}),
+					),
+				}}
+			},
+		},
+		{
+			name: "acl_mode is validated",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config:      m.providerConfig(config("secret")),
+						ExpectError: regexp.MustCompile(`The ACL mode must be either public or private`),
+					},
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+
+  dataset {
+    name     = "staging"
+    acl_mode = "secret"
+  }
+}
+`),
+						ExpectError: regexp.MustCompile(`The ACL mode must be either public or private`),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectResource_datasets(t *testing.T) {
+	config := func(datasets ...string) string {
+		var blocks string
+		for i := 0; i < len(datasets); i += 2 {
+			blocks += fmt.Sprintf(`
+  dataset {
+    name     = %q
+    acl_mode = %q
+  }
+`, datasets[i], datasets[i+1])
+		}
+		return fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name = "Test"
+%s}
+`, blocks)
+	}
+
+	// checkDatasets checks the datasets of the mock project, as a map of their
+	// names to their ACL modes
+	checkDatasets := func(m *mockSanity, want map[string]string) resource.TestCheckFunc {
+		return testCheckMock(func() error {
+			got := map[string]string{}
+			m.withProject("p1", func(p *mockProject) {
+				for _, d := range p.datasets {
+					got[d.Name] = d.AclMode
+				}
+			})
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				return fmt.Errorf("expected datasets %v, got %v", want, got)
+			}
+			return nil
+		})
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "inline datasets are added, changed and removed",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config("staging", "private")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "dataset.#", "1"),
+							checkDatasets(m, map[string]string{"staging": "private"}),
+						),
+					},
+					{
+						// a dataset that is not listed is left alone
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.datasets = append(p.datasets, sanity.Dataset{Name: "other", AclMode: "public"})
+							})
+						},
+						Config: m.providerConfig(config("staging", "private", "production", "public")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "dataset.#", "2"),
+							checkDatasets(m, map[string]string{"staging": "private", "production": "public", "other": "public"}),
+						),
+					},
+					{
+						Config: m.providerConfig(config("staging", "public", "production", "public")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckTypeSetElemNestedAttrs("sanity_project.test", "dataset.*", map[string]string{"name": "staging", "acl_mode": "public"}),
+							checkDatasets(m, map[string]string{"staging": "public", "production": "public", "other": "public"}),
+						),
+					},
+					{
+						Config: m.providerConfig(config("staging", "public")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "dataset.#", "1"),
+							checkDatasets(m, map[string]string{"staging": "public", "other": "public"}),
+						),
+					},
+					{
+						Config:   m.providerConfig(config("staging", "public")),
+						PlanOnly: true,
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectResource_metadata(t *testing.T) {
+	checkMetadata := func(m *mockSanity, want map[string]string) resource.TestCheckFunc {
+		return testCheckMock(func() error {
+			if got := m.project("p1").Metadata; fmt.Sprint(got) != fmt.Sprint(want) {
+				return fmt.Errorf("expected metadata %v, got %v", want, got)
+			}
+			return nil
+		})
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "custom metadata keys are set and removed",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+
+  metadata = {
+    team = "web"
+  }
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "metadata.team", "web"),
+							checkMetadata(m, map[string]string{"team": "web"}),
+						),
+					},
+					{
+						// an entry that is set outside of Terraform is left alone
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.project.Metadata["owner"] = "ops"
+							})
+						},
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+
+  metadata = {
+    team = "web"
+    tier = "gold"
+  }
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "metadata.tier", "gold"),
+							resource.TestCheckResourceAttr("sanity_project.test", "all_metadata.owner", "ops"),
+							checkMetadata(m, map[string]string{"team": "web", "tier": "gold", "owner": "ops"}),
+						),
+					},
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+
+  metadata = {
+    tier = "gold"
+  }
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckNoResourceAttr("sanity_project.test", "metadata.team"),
+							resource.TestCheckNoResourceAttr("sanity_project.test", "all_metadata.team"),
+							checkMetadata(m, map[string]string{"tier": "gold", "owner": "ops"}),
+						),
+					},
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckNoResourceAttr("sanity_project.test", "metadata.%"),
+							checkMetadata(m, map[string]string{"owner": "ops"}),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "a metadata change does not update the project",
+			steps: func(m *mockSanity) []resource.TestStep {
+				config := func(team string) string {
+					return m.providerConfig(fmt.Sprintf(`
+resource "sanity_project" "test" {
+  name  = "Test"
+  color = "#aabbcc"
+
+  metadata = {
+    team = %q
+  }
+}
+`, team))
+				}
+
+				var updates int
+				return []resource.TestStep{
+					{
+						Config: config("web"),
+					},
+					{
+						PreConfig: func() {
+							updates = len(m.requestsTo("PATCH", "/projects/p1"))
+						},
+						Config: config("mobile"),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							checkMetadata(m, map[string]string{"team": "mobile", "color": "#aabbcc"}),
+							testCheckMock(func() error {
+								requests := m.requestsTo("PATCH", "/projects/p1")
+								if len(requests) != updates+1 {
+									return fmt.Errorf("expected 1 update request, got %d", len(requests)-updates)
+								}
+
+								var body map[string]interface{}
+								if err := json.Unmarshal(requests[len(requests)-1].Body, &body); err != nil {
+									return err
+								}
+								if _, ok := body["metadata"]; !ok || len(body) != 1 {
+									return fmt.Errorf("expected only the metadata to be updated, got %v", body)
+								}
+								return nil
+							}),
+						),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectResource_organization(t *testing.T) {
+	// checkCreateRequest checks the organization ID in the request that
+	// created the project, where an empty want means that it is left out
+	checkCreateRequest := func(m *mockSanity, want string) resource.TestCheckFunc {
+		return testCheckMock(func() error {
+			requests := m.requestsTo("POST", "/projects")
+			if len(requests) != 1 {
+				return fmt.Errorf("expected 1 create request, got %d", len(requests))
+			}
+
+			var body map[string]interface{}
+			if err := json.Unmarshal(requests[0].Body, &body); err != nil {
+				return err
+			}
+			organization, ok := body["organizationId"]
+			if want == "" && ok {
+				return fmt.Errorf("expected no organizationId in the create request, got %q", organization)
+			}
+			if want != "" && organization != want {
+				return fmt.Errorf("expected organizationId %q in the create request, got %v", want, organization)
+			}
+			return nil
+		})
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "a personal project is created without an organization",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "organization", ""),
+							checkCreateRequest(m, ""),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "a project is created in the organization",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_project" "test" {
+  name         = "Test"
+  organization = "org1"
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project.test", "organization", "org1"),
+							checkCreateRequest(m, "org1"),
+						),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectResource_createRollback(t *testing.T) {
+	config := `
+resource "sanity_project" "test" {
+  name     = "Test"
+  color    = "#aabbcc"
+  metadata = {
+    team = "web"
+  }
+
+  initial_dataset {
+    name     = "production"
+    acl_mode = "public"
+  }
+
+  dataset {
+    name     = "staging"
+    acl_mode = "private"
+  }
+}
+`
+
+	// checkRolledBack checks that the project was deleted again, or never
+	// created when no delete is expected. The checks of a step that expects an
+	// error do not run, so it runs in a step without resources.
+	checkRolledBack := func(m *mockSanity, deletes int) resource.TestStep {
+		return resource.TestStep{
+			Config: m.providerConfig(""),
+			Check: resource.ComposeAggregateTestCheckFunc(
+				testCheckRequestCount(m, "DELETE", "/projects/p1", deletes),
+				testCheckMock(func() error {
+					if m.project("p1") != nil {
+						return fmt.Errorf("project p1 was not rolled back")
+					}
+					return nil
+				}),
+			),
+		}
+	}
+
+	// failAt makes a step of the creation fail
+	failAt := func(name string, setup func(m *mockSanity), deletes int) mockTestCase {
+		return mockTestCase{
+			name:  name,
+			setup: setup,
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config:      m.providerConfig(config),
+						ExpectError: regexp.MustCompile(`injected\s+500`),
+					},
+					checkRolledBack(m, deletes),
+				}
+			},
+		}
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		failAt("creating the project", func(m *mockSanity) {
+			m.fail("POST", "/projects", 500)
+		}, 0),
+		failAt("waiting for the project", func(m *mockSanity) {
+			m.fail("GET", "/projects/p1", 500)
+		}, 1),
+		failAt("listing the default CORS entries", func(m *mockSanity) {
+			m.fail("GET", "/projects/p1/cors", 500)
+		}, 1),
+		failAt("deleting the default CORS entries", func(m *mockSanity) {
+			m.fail("DELETE", "/projects/p1/cors/.*", 500)
+		}, 1),
+		failAt("applying the settings", func(m *mockSanity) {
+			m.fail("PATCH", "/projects/p1", 500)
+		}, 1),
+		failAt("setting the metadata", func(m *mockSanity) {
+			m.handle("PATCH", "/projects/p1", func(w http.ResponseWriter, r *http.Request) bool {
+				body, _ := io.ReadAll(r.Body)
+				if !strings.Contains(string(body), `"team"`) {
+					r.Body = io.NopCloser(bytes.NewReader(body))
+					return false
+				}
+				writeError(w, 500, "injected 500 for the metadata")
+				return true
+			})
+		}, 1),
+		failAt("creating the initial dataset", func(m *mockSanity) {
+			m.fail("PUT", "/projects/p1/datasets/production", 500)
+		}, 1),
+		failAt("creating the inline datasets", func(m *mockSanity) {
+			m.fail("PUT", "/projects/p1/datasets/staging", 500)
+		}, 1),
+		{
+			name: "the rollback fails",
+			setup: func(m *mockSanity) {
+				m.fail("PATCH", "/projects/p1", 500)
+				m.fail("DELETE", "/projects/p1", 500)
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config:      m.providerConfig(config),
+						ExpectError: regexp.MustCompile(`Project p1 was created but could not be\s+deleted`),
+					},
+					{
+						Config: m.providerConfig(""),
+						Check: testCheckMock(func() error {
+							if m.project("p1") == nil {
+								return fmt.Errorf("expected project p1 to be left behind")
+							}
+							return nil
+						}),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectResource_waitForProject(t *testing.T) {
+	config := `
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`
+
+	defer func(timeout, interval time.Duration) {
+		projectReadyTimeout, projectReadyPollInterval = timeout, interval
+	}(projectReadyTimeout, projectReadyPollInterval)
+	projectReadyTimeout = time.Second
+	projectReadyPollInterval = 10 * time.Millisecond
+
+	// notReady answers the first n requests for project p1 with a 404, as the
+	// Sanity API can right after the project was created
+	notReady := func(m *mockSanity, n int) {
+		var served int
+		m.handle("GET", "/projects/p1", func(w http.ResponseWriter, r *http.Request) bool {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+
+			if n >= 0 && served >= n {
+				return false
+			}
+			served++
+			writeError(w, http.StatusNotFound, "Project not found")
+			return true
+		})
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "the project becomes ready",
+			setup: func(m *mockSanity) {
+				notReady(m, 3)
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config: m.providerConfig(config),
+					Check: resource.ComposeAggregateTestCheckFunc(
+						resource.TestCheckResourceAttr("sanity_project.test", "id", "p1"),
+						testCheckMock(func() error {
+							m.mu.Lock()
+							defer m.mu.Unlock()
+
+							// the default CORS entries are only listed once
+							// the project was returned
+							var polls int
+							for _, r := range m.requests {
+								switch {
+								case r.Method == "GET" && r.Path == "/projects/p1":
+									polls++
+								case r.Method == "GET" && r.Path == "/projects/p1/cors":
+									if polls < 4 {
+										return fmt.Errorf("expected the project to be polled until it was ready, got %d requests before the CORS entries were listed", polls)
+									}
+									return nil
+								}
+							}
+							return fmt.Errorf("expected the default CORS entries to be listed")
+						}),
+					),
+				}}
+			},
+		},
+		{
+			name: "the project does not become ready",
+			setup: func(m *mockSanity) {
+				notReady(m, -1)
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config:      m.providerConfig(config),
+						ExpectError: regexp.MustCompile(`Project p1 was created but was not ready after 1s`),
+					},
+					{
+						Config: m.providerConfig(""),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							testCheckRequestCount(m, "GET", "/projects/p1/cors", 0),
+							testCheckMock(func() error {
+								if m.project("p1") != nil {
+									return fmt.Errorf("project p1 was not rolled back")
+								}
+								return nil
+							}),
+						),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectResource_cancelled(t *testing.T) {
+	// cancelAfter returns a context that is cancelled once the mock served the
+	// first request that matches the method and path
+	cancelAfter := func(m *mockSanity, method string, path string) context.Context {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		m.handle(method, path, func(w http.ResponseWriter, r *http.Request) bool {
+			cancel()
+			return false
+		})
+		return ctx
+	}
+
+	newProjectResource := func(m *mockSanity) *ProjectResource {
+		return newResourceTest(t, m, NewProjectResource()).r.(*ProjectResource)
+	}
+
+	t.Run("removing the default CORS entries", func(t *testing.T) {
+		m := newMockSanity(t)
+		m.addProject("p1", "Test")
+		m.withProject("p1", func(p *mockProject) {
+			for i := int64(101); i <= 103; i++ {
+				p.cors = append(p.cors, sanity.CORSEntry{Id: i, Origin: fmt.Sprintf("http://localhost:%d", i), ProjectId: "p1"})
+			}
+		})
+		ctx := cancelAfter(m, "DELETE", "/projects/p1/cors/.*")
+
+		err := newProjectResource(m).removeDefaultCORSEntries(ctx, "p1")
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected the loop to stop with a cancellation, got %v", err)
+		}
+		if n := len(m.requestsTo("DELETE", "/projects/p1/cors/.*")); n != 1 {
+			t.Fatalf("expected the loop to stop after the first delete, got %d requests", n)
+		}
+	})
+
+	t.Run("deleting inline datasets", func(t *testing.T) {
+		m := newMockSanity(t)
+		m.addProject("p1", "Test")
+		m.withProject("p1", func(p *mockProject) {
+			p.datasets = []sanity.Dataset{{Name: "a", AclMode: "public"}, {Name: "b", AclMode: "public"}}
+		})
+		ctx := cancelAfter(m, "DELETE", "/projects/p1/datasets/.*")
+
+		prior := []ProjectResourceDatasetModel{
+			{Name: types.String{Value: "a"}, AclMode: types.String{Value: "public"}},
+			{Name: types.String{Value: "b"}, AclMode: types.String{Value: "public"}},
+		}
+		diags := newProjectResource(m).reconcileDatasets(ctx, "p1", prior, nil)
+		requireErrorDiagnostic(t, diags, "Operation Cancelled")
+		if n := len(m.requestsTo("DELETE", "/projects/p1/datasets/.*")); n != 1 {
+			t.Fatalf("expected the loop to stop after the first delete, got %d requests", n)
+		}
+	})
+
+	t.Run("waiting for the project", func(t *testing.T) {
+		defer func(interval time.Duration) {
+			projectReadyPollInterval = interval
+		}(projectReadyPollInterval)
+		projectReadyPollInterval = 10 * time.Millisecond
+
+		// the project never becomes ready
+		m := newMockSanity(t)
+		ctx := cancelAfter(m, "GET", "/projects/p1")
+
+		start := time.Now()
+		diags := newProjectResource(m).waitForProject(ctx, "p1")
+		requireErrorDiagnostic(t, diags, "Operation Cancelled")
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("expected the wait to stop promptly, took %s", elapsed)
+		}
+	})
+}
+
+func TestNormalizeStudioHost(t *testing.T) {
+	for input, want := range map[string]string{
+		"test":                        "test",
+		"test.sanity.studio":          "test",
+		"https://test.sanity.studio":  "test",
+		"https://test.sanity.studio/": "test",
+		"http://test.sanity.studio":   "test",
+		"":                            "",
+	} {
+		if got := normalizeStudioHost(input); got != want {
+			t.Errorf("normalizeStudioHost(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &ProjectRolesDataSource{}
+
+func NewProjectRolesDataSource() datasource.DataSource {
+	return &ProjectRolesDataSource{}
+}
+
+// ProjectRolesDataSource defines the data source implementation.
+type ProjectRolesDataSource struct {
+	client *Client
+}
+
+// ProjectRolesDataSourceModel describes the data source data model.
+type ProjectRolesDataSourceModel struct {
+	Project types.String                      `tfsdk:"project"`
+	Roles   []ProjectRolesDataSourceRoleModel `tfsdk:"roles"`
+}
+
+// ProjectRolesDataSourceRoleModel describes a single role in the data source
+// data model.
+type ProjectRolesDataSourceRoleModel struct {
+	Name        types.String `tfsdk:"name"`
+	Title       types.String `tfsdk:"title"`
+	Description types.String `tfsdk:"description"`
+}
+
+func (d *ProjectRolesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_roles"
+}
+
+func (d *ProjectRolesDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets all the roles available in a Sanity project, including custom roles. Role names can be assigned to project members and tokens.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the roles belong to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"roles": {
+				MarkdownDescription: "The roles available in the project.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"name": {
+						MarkdownDescription: "The name of the role (e.g. `administrator`).",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"title": {
+						MarkdownDescription: "The display-friendly name of the role (e.g. `Administrator`).",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"description": {
+						MarkdownDescription: "A short text that explains the capabilities of the role.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *ProjectRolesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ProjectRolesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ProjectRolesDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	roles, err := d.client.ListProjectRoles(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Roles = make([]ProjectRolesDataSourceRoleModel, 0, len(roles))
+	for _, role := range roles {
+		data.Roles = append(data.Roles, ProjectRolesDataSourceRoleModel{
+			Name:        types.String{Value: role.Name},
+			Title:       types.String{Value: role.Title},
+			Description: types.String{Value: role.Description},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
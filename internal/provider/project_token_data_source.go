@@ -0,0 +1,133 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &ProjectTokenDataSource{}
+
+func NewProjectTokenDataSource() datasource.DataSource {
+	return &ProjectTokenDataSource{}
+}
+
+// ProjectTokenDataSource defines the data source implementation.
+type ProjectTokenDataSource struct {
+	client *Client
+}
+
+// ProjectTokenDataSourceModel describes the data source data model.
+type ProjectTokenDataSourceModel struct {
+	Id      types.String `tfsdk:"id"`
+	Project types.String `tfsdk:"project"`
+	Label   types.String `tfsdk:"label"`
+	Roles   types.List   `tfsdk:"roles"`
+}
+
+func (d *ProjectTokenDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_token"
+}
+
+func (d *ProjectTokenDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets a Sanity project token by its label. The token key is only available when the token is created, so it is not returned by this data source.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				MarkdownDescription: "The unique token ID generated by Sanity.",
+				Type:                types.StringType,
+				Computed:            true,
+			},
+			"project": {
+				MarkdownDescription: "The ID of the project that the token belongs to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"label": {
+				MarkdownDescription: "The label of the token. The label must match exactly one token in the project.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"roles": {
+				MarkdownDescription: "The names of the roles assigned to the token.",
+				Type:                types.ListType{ElemType: types.StringType},
+				Computed:            true,
+			},
+		},
+	}, nil
+}
+
+func (d *ProjectTokenDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ProjectTokenDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ProjectTokenDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	tokens, err := d.client.Projects.ListProjectTokens(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var matches []sanity.ProjectToken
+	for _, t := range tokens {
+		if t.Label == data.Label.Value {
+			matches = append(matches, t)
+		}
+	}
+
+	if len(matches) == 0 {
+		resp.Diagnostics.AddError("project token not found", fmt.Sprintf("No token with the label %q was found in project %s", data.Label.Value, data.Project.Value))
+		return
+	}
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError("multiple project tokens found", fmt.Sprintf("%d tokens with the label %q were found in project %s. Give the tokens distinct labels to look one up.", len(matches), data.Label.Value, data.Project.Value))
+		return
+	}
+
+	token := matches[0]
+
+	data.Id = types.String{Value: token.Id}
+	data.Roles = types.List{ElemType: types.StringType}
+	for _, role := range token.Roles {
+		data.Roles.Elems = append(data.Roles.Elems, types.String{Value: role.Name})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
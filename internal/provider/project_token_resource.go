@@ -2,25 +2,33 @@ package provider
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/tessellator/go-sanity/sanity"
 )
 
 var _ resource.Resource = &ProjectTokenResource{}
 var _ resource.ResourceWithImportState = &ProjectTokenResource{}
+var _ resource.ResourceWithUpgradeState = &ProjectTokenResource{}
+var _ resource.ResourceWithModifyPlan = &ProjectTokenResource{}
 
 func NewProjectTokenResource() resource.Resource {
 	return &ProjectTokenResource{}
 }
 
 type ProjectTokenResource struct {
-	client *sanity.Client
+	client *Client
 }
 
 type ProjectTokenResourceModel struct {
@@ -28,7 +36,13 @@ type ProjectTokenResourceModel struct {
 	Project  types.String `tfsdk:"project"`
 	Label    types.String `tfsdk:"label"`
 	RoleName types.String `tfsdk:"role_name"`
+	Roles    types.List   `tfsdk:"roles"`
 	Key      types.String `tfsdk:"key"`
+
+	Fingerprint types.String `tfsdk:"fingerprint"`
+
+	RotateTrigger types.String `tfsdk:"rotate_trigger"`
+	Token         types.String `tfsdk:"token"`
 }
 
 func (r *ProjectTokenResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -37,9 +51,16 @@ func (r *ProjectTokenResource) Metadata(ctx context.Context, req resource.Metada
 
 func (r *ProjectTokenResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
 	return tfsdk.Schema{
-		MarkdownDescription: "Provides a Sanity project token. The token key is a sensitive value that can be used to make authenticated requests against the Sanity HTTP API.",
+		MarkdownDescription: "Provides a Sanity project token. The token key is a sensitive value that can be used to make authenticated requests against the Sanity HTTP API.\n\nAn existing token can be imported, but its key cannot be retrieved and is left empty.",
+		Version:             1,
 
 		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this token instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
 			"id": {
 				Computed:            true,
 				MarkdownDescription: "The unique token ID generated by Sanity.",
@@ -66,37 +87,170 @@ func (r *ProjectTokenResource) GetSchema(ctx context.Context) (tfsdk.Schema, dia
 			},
 			"role_name": {
 				Required:            true,
-				MarkdownDescription: "The role name that indicates which permissions are assigned to the token. For a free account, valid values are `viewer`, `editor`, and `deploy-studio`.",
+				MarkdownDescription: "The role name that indicates which permissions are assigned to the token. Most tokens use `viewer`, `editor`, or `deploy-studio`, which are also the only valid values for a free account; the `sanity_project_roles` data source lists the roles of a project. The role is checked against the roles of the project when the token is planned with a new role. Sanity does not allow the role of a token to be changed, so changing this value, or the role being changed outside of Terraform, forces a new token to be created.",
 				PlanModifiers: tfsdk.AttributePlanModifiers{
 					resource.RequiresReplace(),
 				},
 				Type: types.StringType,
 			},
+			"roles": {
+				Computed:            true,
+				MarkdownDescription: "The names of the roles that Sanity has assigned to the token. Sanity creates a token with the single role given by `role_name` and does not accept a list of roles, so this attribute is read-only.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+				Type: types.ListType{ElemType: types.StringType},
+			},
 			"key": {
 				Computed:            true,
 				Sensitive:           true,
-				MarkdownDescription: "The token value. This value can be used for making authenticated requests against the API with the permissions indicated by the role name.",
+				MarkdownDescription: "The token value. This value can be used for making authenticated requests against the API with the permissions indicated by the role name. Sanity only returns the key when the token is created, so it is kept in state and never refreshed.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+				Type: types.StringType,
+			},
+			"fingerprint": {
+				Computed:            true,
+				MarkdownDescription: "A fingerprint of the token, used to detect that the token was replaced outside of Terraform. Sanity does not expose a fingerprint of the key, so it is derived from the ID, label, roles and creation time of the token. When the fingerprint no longer matches, `key` is cleared and the next apply replaces the token, deleting the old one. It cannot detect a leaked key or anything else that does not change these values.",
 				PlanModifiers: tfsdk.AttributePlanModifiers{
 					resource.UseStateForUnknown(),
 				},
 				Type: types.StringType,
 			},
+			"rotate_trigger": {
+				Optional:            true,
+				MarkdownDescription: "An arbitrary value that rotates the token when changed. Rotating creates a new token with the same label and role, deletes the old token, and changes `id` and `key`, so anything that uses the key picks up the new value.",
+				Type:                types.StringType,
+			},
 		},
 	}, nil
 }
 
+func (r *ProjectTokenResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// nothing to check on destroy
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan *ProjectTokenResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, plan.Token)
+
+	// nothing to rotate on create
+	if req.State.Raw.IsNull() {
+		r.validateRoleName(ctx, plan, resp)
+		return
+	}
+
+	var state *ProjectTokenResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the roles of the project are only listed when the token gets a new role,
+	// so that planning existing tokens does not send a request for each token
+	if !plan.RoleName.Equal(state.RoleName) || !plan.Project.Equal(state.Project) {
+		r.validateRoleName(ctx, plan, resp)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Read clears the key when the token no longer matches its fingerprint, so
+	// the token is replaced and Delete still removes the old one
+	if state.Key.Null {
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("key"))
+	} else if plan.RotateTrigger.Equal(state.RotateTrigger) {
+		return
+	}
+
+	plan.Id = types.String{Unknown: true}
+	plan.Key = types.String{Unknown: true}
+	plan.Fingerprint = types.String{Unknown: true}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// validateRoleName checks that the role exists in the project. The check is
+// skipped when the provider is not configured yet or the values are unknown.
+func (r *ProjectTokenResource) validateRoleName(ctx context.Context, plan *ProjectTokenResourceModel, resp *resource.ModifyPlanResponse) {
+	if r.client == nil || plan.Project.Unknown || plan.RoleName.Unknown {
+		return
+	}
+
+	roles, err := r.client.ListProjectRoles(ctx, plan.Project.Value)
+	if isNotFound(err) {
+		// the project is new or was deleted, so there is nothing to check against
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role.Name == plan.RoleName.Value {
+			return
+		}
+		names = append(names, role.Name)
+	}
+
+	resp.Diagnostics.AddAttributeError(
+		path.Root("role_name"),
+		"Invalid Role Name",
+		fmt.Sprintf("The role %q does not exist in project %s. Valid roles are: %s", plan.RoleName.Value, plan.Project.Value, strings.Join(names, ", ")),
+	)
+}
+
+// tokenFingerprint derives a fingerprint from the metadata of the token, or
+// returns an empty string if Sanity did not report when the token was created.
+func tokenFingerprint(token sanity.ProjectToken) string {
+	if token.CreatedAt.IsZero() {
+		return ""
+	}
+
+	roles := make([]string, 0, len(token.Roles))
+	for _, role := range token.Roles {
+		roles = append(roles, role.Name)
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n%s", token.Id, token.Label, strings.Join(roles, ","), token.CreatedAt.UTC().Format(time.RFC3339Nano))
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+func tokenRolesList(roles []sanity.Role) types.List {
+	list := types.List{ElemType: types.StringType, Elems: []attr.Value{}}
+	for _, role := range roles {
+		list.Elems = append(list.Elems, types.String{Value: role.Name})
+	}
+	return list
+}
+
 func (r *ProjectTokenResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*sanity.Client)
+	client, ok := req.ProviderData.(*Client)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
@@ -106,6 +260,11 @@ func (r *ProjectTokenResource) Configure(ctx context.Context, req resource.Confi
 }
 
 func (r *ProjectTokenResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *ProjectTokenResourceModel
 
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
@@ -114,17 +273,22 @@ func (r *ProjectTokenResource) Create(ctx context.Context, req resource.CreateRe
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity project token", map[string]interface{}{"project": data.Project.Value, "label": data.Label.Value})
+
 	tokenResp, err := r.client.Projects.CreateProjectToken(ctx, data.Project.Value, &sanity.CreateProjectTokenRequest{
 		Label:    data.Label.Value,
 		RoleName: data.RoleName.Value,
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
 	data.Id = types.String{Value: tokenResp.Id}
 	data.Key = types.String{Value: tokenResp.Key}
+	data.Roles = tokenRolesList(tokenResp.Roles)
+	data.Fingerprint = types.String{Value: tokenFingerprint(tokenResp.ProjectToken)}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
@@ -139,6 +303,9 @@ func (r *ProjectTokenResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity project token", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Entry id is null", "Entry id is null")
 		return
@@ -149,8 +316,12 @@ func (r *ProjectTokenResource) Read(ctx context.Context, req resource.ReadReques
 	}
 
 	tokens, err := r.client.Projects.ListProjectTokens(ctx, data.Project.Value)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", err.Error())
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
 		return
 	}
 
@@ -165,23 +336,100 @@ func (r *ProjectTokenResource) Read(ctx context.Context, req resource.ReadReques
 		}
 	}
 	if !found {
-		resp.Diagnostics.AddError("project token not found", "project token not found")
+		// the token was deleted outside of Terraform
+		resp.State.RemoveResource(ctx)
 		return
 	}
 
+	// the fingerprint is empty when Sanity did not report the creation time of
+	// the token, and is then only filled in
+	fingerprint := tokenFingerprint(token)
+	if fingerprint != "" && data.Fingerprint.Value != "" && fingerprint != data.Fingerprint.Value {
+		resp.Diagnostics.AddWarning(
+			"Project token changed outside of Terraform",
+			fmt.Sprintf("Token %s in project %s no longer matches the token that Terraform created, so its key is unknown. The token will be deleted and a new one created on the next apply.", data.Id.Value, data.Project.Value),
+		)
+		data.Key = types.String{Null: true}
+	}
+	if fingerprint != "" || data.Fingerprint.Null {
+		data.Fingerprint = types.String{Value: fingerprint}
+	}
+
 	data.Label = types.String{Value: token.Label}
-	// we cannot actually capture the delta on role_name because it is an
-	// ephemeral value... it is only used to populate roles and is not stored as
-	// part of the actual resource on Sanity's side
+	data.Roles = tokenRolesList(token.Roles)
+	// the key is only returned when the token is created, so the value already
+	// in state is kept as-is rather than blanked out
+
+	// role_name is not stored by Sanity, so it is only changed when the token
+	// no longer has that role, which plans a replacement
+	hasRole := false
+	for _, role := range token.Roles {
+		if role.Name == data.RoleName.Value {
+			hasRole = true
+			break
+		}
+	}
+	if !hasRole && len(token.Roles) > 0 {
+		data.RoleName = types.String{Value: token.Roles[0].Name}
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ProjectTokenResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	resp.Diagnostics.AddError("Provider Error", "Update is not supported on project tokens")
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data, state *ProjectTokenResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity project token", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	// Every other attribute forces a replacement, so an update means the rotate
+	// trigger changed. The new token is created before the old one is deleted
+	// so that a failure never leaves the project without a token.
+	if !data.RotateTrigger.Equal(state.RotateTrigger) {
+		tokenResp, err := r.client.Projects.CreateProjectToken(ctx, data.Project.Value, &sanity.CreateProjectTokenRequest{
+			Label:    data.Label.Value,
+			RoleName: data.RoleName.Value,
+		})
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostic(err))
+			return
+		}
+
+		data.Id = types.String{Value: tokenResp.Id}
+		data.Key = types.String{Value: tokenResp.Key}
+		data.Roles = tokenRolesList(tokenResp.Roles)
+		data.Fingerprint = types.String{Value: tokenFingerprint(tokenResp.ProjectToken)}
+
+		_, err = r.client.Projects.DeleteProjectToken(ctx, state.Project.Value, state.Id.Value)
+		if err != nil {
+			// the new token exists, so record it before reporting the failure
+			resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			resp.Diagnostics.Append(clientErrorDiagnosticf(err, "token %s was rotated but the old token could not be deleted", state.Id.Value))
+			return
+		}
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *ProjectTokenResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
 	var data *ProjectTokenResourceModel
 
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
@@ -190,6 +438,9 @@ func (r *ProjectTokenResource) Delete(ctx context.Context, req resource.DeleteRe
 		return
 	}
 
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity project token", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
 	if data.Id.Null {
 		resp.Diagnostics.AddError("Entry id is null", "Entry id is null")
 		return
@@ -201,12 +452,108 @@ func (r *ProjectTokenResource) Delete(ctx context.Context, req resource.DeleteRe
 
 	_, err := r.client.Projects.DeleteProjectToken(ctx, data.Project.Value, data.Id.Value)
 
-	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("entry %s could not be deleted, got error: %s", data.Id.Value, err))
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "entry %s could not be deleted", data.Id.Value))
 		return
 	}
 }
 
 func (r *ProjectTokenResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	projectId, tokenId, _ := strings.Cut(req.ID, "/")
+	if projectId == "" || tokenId == "" || strings.Contains(tokenId, "/") {
+		resp.Diagnostics.AddError("Import Error", "The format for importing a project token is project-id/token-id")
+		return
+	}
+
+	tokens, err := r.client.Projects.ListProjectTokens(ctx, projectId)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var token *sanity.ProjectToken
+	for i := range tokens {
+		if tokens[i].Id == tokenId {
+			token = &tokens[i]
+			break
+		}
+	}
+	if token == nil {
+		resp.Diagnostics.AddError("Import Error", fmt.Sprintf("Token %s was not found in project %s", tokenId, projectId))
+		return
+	}
+
+	roleName := ""
+	if len(token.Roles) > 0 {
+		roleName = token.Roles[0].Name
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), resource.ImportStateRequest{ID: tokenId}, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), resource.ImportStateRequest{ID: projectId}, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("label"), token.Label)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("role_name"), roleName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("roles"), tokenRolesList(token.Roles))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), "")...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("fingerprint"), tokenFingerprint(*token))...)
+
+	resp.Diagnostics.AddWarning(
+		"Token key not imported",
+		fmt.Sprintf("Sanity only returns the key of a token when it is created, so the key of token %s is empty in state. Set rotate_trigger to replace the token with a new one whose key is known.", tokenId),
+	)
+}
+
+// projectTokenResourceModelV0 is the model of version 0 of the schema.
+type projectTokenResourceModelV0 struct {
+	Id            types.String `tfsdk:"id"`
+	Project       types.String `tfsdk:"project"`
+	Label         types.String `tfsdk:"label"`
+	RoleName      types.String `tfsdk:"role_name"`
+	Roles         types.List   `tfsdk:"roles"`
+	Key           types.String `tfsdk:"key"`
+	RotateTrigger types.String `tfsdk:"rotate_trigger"`
+	Token         types.String `tfsdk:"token"`
+}
+
+func (r *ProjectTokenResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 has no fingerprint. It is left null and set by the next
+		// read.
+		0: {
+			PriorSchema: &tfsdk.Schema{
+				Attributes: map[string]tfsdk.Attribute{
+					"token":          {Type: types.StringType, Optional: true, Sensitive: true},
+					"id":             {Type: types.StringType, Computed: true},
+					"project":        {Type: types.StringType, Required: true},
+					"label":          {Type: types.StringType, Required: true},
+					"role_name":      {Type: types.StringType, Required: true},
+					"roles":          {Type: types.ListType{ElemType: types.StringType}, Computed: true},
+					"key":            {Type: types.StringType, Computed: true, Sensitive: true},
+					"rotate_trigger": {Type: types.StringType, Optional: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior projectTokenResourceModelV0
+
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data := ProjectTokenResourceModel{
+					Id:            prior.Id,
+					Project:       prior.Project,
+					Label:         prior.Label,
+					RoleName:      prior.RoleName,
+					Roles:         prior.Roles,
+					Key:           prior.Key,
+					Fingerprint:   types.String{Null: true},
+					RotateTrigger: prior.RotateTrigger,
+					Token:         prior.Token,
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+			},
+		},
+	}
 }
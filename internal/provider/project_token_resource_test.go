@@ -0,0 +1,363 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+func TestProjectTokenResource(t *testing.T) {
+	config := `
+resource "sanity_project_token" "test" {
+  project   = "p1"
+  label     = "CI"
+  role_name = "viewer"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "the key survives a refresh",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project_token.test", "id", "tok1"),
+							resource.TestCheckResourceAttr("sanity_project_token.test", "key", "sktok1"),
+						),
+					},
+					{
+						RefreshState: true,
+						Check:        resource.TestCheckResourceAttr("sanity_project_token.test", "key", "sktok1"),
+					},
+					{
+						Config:   m.providerConfig(config),
+						PlanOnly: true,
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectTokenResource_fingerprint(t *testing.T) {
+	config := `
+resource "sanity_project_token" "test" {
+  project   = "p1"
+  label     = "CI"
+  role_name = "viewer"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "a token changed outside of terraform is replaced",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				var id string
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttrSet("sanity_project_token.test", "fingerprint"),
+							resource.TestCheckResourceAttrWith("sanity_project_token.test", "id", func(value string) error {
+								id = value
+								return nil
+							}),
+						),
+					},
+					{
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.tokens[0].CreatedAt = p.tokens[0].CreatedAt.Add(time.Hour)
+							})
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttrPtr("sanity_project_token.test", "id", &id),
+							resource.TestCheckNoResourceAttr("sanity_project_token.test", "key"),
+						),
+					},
+					{
+						Config: m.providerConfig(config),
+						Check: func(s *terraform.State) error {
+							rs := s.RootModule().Resources["sanity_project_token.test"]
+							if rs.Primary.ID == id || rs.Primary.Attributes["key"] != "sk"+rs.Primary.ID {
+								return fmt.Errorf("expected a new token with a key, got %v", rs.Primary.Attributes)
+							}
+
+							var ids []string
+							m.withProject("p1", func(p *mockProject) {
+								for _, token := range p.tokens {
+									ids = append(ids, token.Id)
+								}
+							})
+							if len(ids) != 1 || ids[0] != rs.Primary.ID {
+								return fmt.Errorf("expected the old token to be deleted, got tokens %v", ids)
+							}
+							return nil
+						},
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectTokenResource_rotate(t *testing.T) {
+	config := func(trigger string) string {
+		return fmt.Sprintf(`
+resource "sanity_project_token" "test" {
+  project        = "p1"
+  label          = "CI"
+  role_name      = "viewer"
+  rotate_trigger = %q
+}
+`, trigger)
+	}
+
+	tokenIds := func(m *mockSanity) []string {
+		var ids []string
+		m.withProject("p1", func(p *mockProject) {
+			for _, token := range p.tokens {
+				ids = append(ids, token.Id)
+			}
+		})
+		return ids
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "changing rotate_trigger replaces the key and deletes the old token",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config("1")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project_token.test", "id", "tok1"),
+							resource.TestCheckResourceAttr("sanity_project_token.test", "key", "sktok1"),
+						),
+					},
+					{
+						Config: m.providerConfig(config("2")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttrWith("sanity_project_token.test", "id", func(value string) error {
+								if value == "tok1" {
+									return fmt.Errorf("expected a new token id")
+								}
+								return nil
+							}),
+							func(s *terraform.State) error {
+								rs := s.RootModule().Resources["sanity_project_token.test"]
+								if rs.Primary.Attributes["key"] != "sk"+rs.Primary.ID {
+									return fmt.Errorf("expected the key of the new token, got %q", rs.Primary.Attributes["key"])
+								}
+								if ids := tokenIds(m); len(ids) != 1 || ids[0] != rs.Primary.ID {
+									return fmt.Errorf("expected the old token to be deleted, got tokens %v", ids)
+								}
+								return nil
+							},
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "the new token is kept when the old token cannot be deleted",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config("1")),
+						Check:  resource.TestCheckResourceAttr("sanity_project_token.test", "id", "tok1"),
+					},
+					{
+						PreConfig: func() {
+							m.fail("DELETE", "^/projects/p1/tokens/tok1$", 500)
+						},
+						Config:      m.providerConfig(config("2")),
+						ExpectError: regexp.MustCompile(`token tok1 was rotated but the old token could not be\s+deleted`),
+					},
+					{
+						PreConfig: m.clearFailures,
+						Config:    m.providerConfig(config("2")),
+						PlanOnly:  true,
+					},
+					{
+						Config: m.providerConfig(config("2")),
+						Check: func(s *terraform.State) error {
+							rs := s.RootModule().Resources["sanity_project_token.test"]
+							if rs.Primary.ID == "tok1" || rs.Primary.Attributes["key"] != "sk"+rs.Primary.ID {
+								return fmt.Errorf("expected the new token in state, got %v", rs.Primary.Attributes)
+							}
+							if ids := tokenIds(m); len(ids) != 2 {
+								return fmt.Errorf("expected the old and new tokens to exist, got tokens %v", ids)
+							}
+							return nil
+						},
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectTokenResource_roleName(t *testing.T) {
+	config := func(roleName string) string {
+		return fmt.Sprintf(`
+resource "sanity_project_token" "test" {
+  project   = "p1"
+  label     = "CI"
+  role_name = %q
+}
+`, roleName)
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "an unknown role is rejected",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{{
+					Config:      m.providerConfig(config("owner")),
+					ExpectError: regexp.MustCompile(`The role "owner" does not exist in project p1`),
+				}}
+			},
+		},
+		{
+			name: "the roles are only listed for a new role",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config("viewer")),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_project_token.test", "roles.#", "1"),
+							resource.TestCheckResourceAttr("sanity_project_token.test", "roles.0", "viewer"),
+						),
+					},
+					{
+						PreConfig: func() {
+							m.fail("GET", "/projects/p1/roles", 500)
+						},
+						Config:   m.providerConfig(config("viewer")),
+						PlanOnly: true,
+					},
+					{
+						Config:      m.providerConfig(config("editor")),
+						ExpectError: regexp.MustCompile(`injected\s+500`),
+					},
+					{
+						PreConfig: func() {
+							m.clearFailures()
+						},
+						Config: m.providerConfig(config("editor")),
+						Check:  resource.TestCheckResourceAttr("sanity_project_token.test", "roles.0", "editor"),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestProjectTokenResource_import(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+	m.withProject("p1", func(p *mockProject) {
+		p.tokens = []sanity.ProjectToken{{
+			Id:        "tok101",
+			Label:     "CI",
+			Roles:     []sanity.Role{{Name: "editor"}},
+			CreatedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		}}
+	})
+
+	rt := newResourceTest(t, m, NewProjectTokenResource())
+
+	state, diags := rt.importState("p1/tok101")
+	if diags.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", diags)
+	}
+	if len(diags.Warnings()) != 1 {
+		t.Fatalf("expected a warning that the key is not imported, got: %v", diags)
+	}
+
+	data := stateModel[ProjectTokenResourceModel](t, state)
+	if data.Id.Value != "tok101" || data.Project.Value != "p1" || data.Label.Value != "CI" || data.RoleName.Value != "editor" || data.Key.Value != "" || data.Fingerprint.Value == "" {
+		t.Fatalf("unexpected imported state: %+v", data)
+	}
+
+	state, diags = rt.read(state)
+	requireNoDiagnostics(t, diags)
+	if data := stateModel[ProjectTokenResourceModel](t, state); data.Id.Value != "tok101" || data.RoleName.Value != "editor" {
+		t.Fatalf("expected the imported token to be read, got %+v", data)
+	}
+
+	for _, id := range []string{"tok101", "p1/", "/tok101", "p1/tok101/extra", ""} {
+		_, diags := rt.importState(id)
+		requireErrorDiagnostic(t, diags, "The format for importing a project token is project-id/token-id")
+	}
+
+	_, diags = rt.importState("p1/tok102")
+	requireErrorDiagnostic(t, diags, "Token tok102 was not found in project p1")
+}
+
+func TestProjectTokenResource_upgradeFromV0(t *testing.T) {
+	m := newMockSanity(t)
+	m.addProject("p1", "Test")
+	m.withProject("p1", func(p *mockProject) {
+		p.tokens = []sanity.ProjectToken{{
+			Id:        "tok101",
+			Label:     "CI",
+			Roles:     []sanity.Role{{Name: "viewer"}},
+			CreatedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		}}
+	})
+
+	rt := newResourceTest(t, m, NewProjectTokenResource())
+	state, diags := rt.upgradeState(0, `{
+  "id": "tok101",
+  "project": "p1",
+  "label": "CI",
+  "role_name": "viewer",
+  "roles": ["viewer"],
+  "key": "sk-secret",
+  "rotate_trigger": null,
+  "token": null
+}`)
+	requireNoDiagnostics(t, diags)
+
+	data := stateModel[ProjectTokenResourceModel](t, state)
+	if data.Id.Value != "tok101" || data.Key.Value != "sk-secret" || data.RoleName.Value != "viewer" || !data.Fingerprint.Null {
+		t.Fatalf("unexpected upgraded state: %+v", data)
+	}
+
+	state, diags = rt.read(state)
+	requireNoDiagnostics(t, diags)
+
+	data = stateModel[ProjectTokenResourceModel](t, state)
+	if data.Id.Value != "tok101" || data.Key.Value != "sk-secret" || data.Fingerprint.Value == "" {
+		t.Fatalf("expected the read to keep the token and set the fingerprint, got %+v", data)
+	}
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &ProjectTokensDataSource{}
+
+func NewProjectTokensDataSource() datasource.DataSource {
+	return &ProjectTokensDataSource{}
+}
+
+// ProjectTokensDataSource defines the data source implementation.
+type ProjectTokensDataSource struct {
+	client *Client
+}
+
+// ProjectTokensDataSourceModel describes the data source data model.
+type ProjectTokensDataSourceModel struct {
+	Project types.String                        `tfsdk:"project"`
+	Tokens  []ProjectTokensDataSourceTokenModel `tfsdk:"tokens"`
+}
+
+// ProjectTokensDataSourceTokenModel describes a single token in the data
+// source data model.
+type ProjectTokensDataSourceTokenModel struct {
+	Id    types.String `tfsdk:"id"`
+	Label types.String `tfsdk:"label"`
+	Roles types.List   `tfsdk:"roles"`
+}
+
+func (d *ProjectTokensDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_project_tokens"
+}
+
+func (d *ProjectTokensDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets all the tokens in a Sanity project, including tokens created outside of Terraform. The token keys are never returned.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the tokens belong to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"tokens": {
+				MarkdownDescription: "The tokens in the project, sorted by ID.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						MarkdownDescription: "The unique token ID generated by Sanity.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"label": {
+						MarkdownDescription: "The label of the token.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"roles": {
+						MarkdownDescription: "The names of the roles assigned to the token.",
+						Type:                types.ListType{ElemType: types.StringType},
+						Computed:            true,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *ProjectTokensDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ProjectTokensDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ProjectTokensDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	tokens, err := d.client.Projects.ListProjectTokens(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Id < tokens[j].Id
+	})
+
+	data.Tokens = make([]ProjectTokensDataSourceTokenModel, 0, len(tokens))
+	for _, token := range tokens {
+		roles := types.List{ElemType: types.StringType, Elems: []attr.Value{}}
+		for _, role := range token.Roles {
+			roles.Elems = append(roles.Elems, types.String{Value: role.Name})
+		}
+
+		data.Tokens = append(data.Tokens, ProjectTokensDataSourceTokenModel{
+			Id:    types.String{Value: token.Id},
+			Label: types.String{Value: token.Label},
+			Roles: roles,
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
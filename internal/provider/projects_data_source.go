@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &ProjectsDataSource{}
+
+func NewProjectsDataSource() datasource.DataSource {
+	return &ProjectsDataSource{}
+}
+
+// ProjectsDataSource defines the data source implementation.
+type ProjectsDataSource struct {
+	client *Client
+}
+
+// ProjectsDataSourceModel describes the data source data model.
+type ProjectsDataSourceModel struct {
+	Organization types.String                     `tfsdk:"organization"`
+	Projects     []ProjectsDataSourceProjectModel `tfsdk:"projects"`
+}
+
+// ProjectsDataSourceProjectModel describes a single project in the data source
+// data model.
+type ProjectsDataSourceProjectModel struct {
+	Id               types.String `tfsdk:"id"`
+	Name             types.String `tfsdk:"name"`
+	Organization     types.String `tfsdk:"organization"`
+	IsDisabledByUser types.Bool   `tfsdk:"disabled_by_user"`
+}
+
+func (d *ProjectsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_projects"
+}
+
+func (d *ProjectsDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets all the Sanity projects that the token has access to, including projects created outside of Terraform.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"organization": {
+				MarkdownDescription: "The ID of an organization. When set, only the projects that belong to the organization are returned.",
+				Type:                types.StringType,
+				Optional:            true,
+			},
+			"projects": {
+				MarkdownDescription: "The projects, sorted by ID.",
+				Computed:            true,
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"id": {
+						MarkdownDescription: "The project ID.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"name": {
+						MarkdownDescription: "The project name.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"organization": {
+						MarkdownDescription: "The ID of the organization that owns the project, or an empty string for a personal project.",
+						Type:                types.StringType,
+						Computed:            true,
+					},
+					"disabled_by_user": {
+						MarkdownDescription: "Indicates whether the project is archived.",
+						Type:                types.BoolType,
+						Computed:            true,
+					},
+				}),
+			},
+		},
+	}, nil
+}
+
+func (d *ProjectsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *ProjectsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data ProjectsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	projects, err := d.client.Projects.List(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Id < projects[j].Id
+	})
+
+	data.Projects = make([]ProjectsDataSourceProjectModel, 0, len(projects))
+	for _, project := range projects {
+		if !data.Organization.Null && project.OrganizationId != data.Organization.Value {
+			continue
+		}
+		data.Projects = append(data.Projects, ProjectsDataSourceProjectModel{
+			Id:               types.String{Value: project.Id},
+			Name:             types.String{Value: project.DisplayName},
+			Organization:     types.String{Value: project.OrganizationId},
+			IsDisabledByUser: types.Bool{Value: project.IsDisabledByUser},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -2,20 +2,32 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/tessellator/go-sanity/sanity"
 	"golang.org/x/oauth2"
 )
 
 var _ provider.Provider = &SanityProvider{}
 var _ provider.ProviderWithMetadata = &SanityProvider{}
+var _ provider.ProviderWithValidateConfig = &SanityProvider{}
+
+const (
+	defaultMaxRetries     = 3
+	defaultRequestTimeout = 30 * time.Second
+)
 
 // SanityProvider defines the provider implementation.
 type SanityProvider struct {
@@ -27,7 +39,23 @@ type SanityProvider struct {
 
 // SanityProviderModel describes the provider data model.
 type SanityProviderModel struct {
-	Token types.String `tfsdk:"token"`
+	Token                       types.String  `tfsdk:"token"`
+	TokenFile                   types.String  `tfsdk:"token_file"`
+	FallbackToken               types.String  `tfsdk:"fallback_token"`
+	UseCLICredentials           types.Bool    `tfsdk:"use_cli_credentials"`
+	ApiURL                      types.String  `tfsdk:"api_url"`
+	ProxyURL                    types.String  `tfsdk:"proxy_url"`
+	MaxRetries                  types.Int64   `tfsdk:"max_retries"`
+	RequestTimeout              types.Int64   `tfsdk:"request_timeout"`
+	RequestsPerSecond           types.Float64 `tfsdk:"requests_per_second"`
+	Organization                types.String  `tfsdk:"organization"`
+	DefaultCORSAllowCredentials types.Bool    `tfsdk:"default_cors_allow_credentials"`
+	ValidateToken               types.Bool    `tfsdk:"validate_token"`
+	ReadOnly                    types.Bool    `tfsdk:"read_only"`
+	RefreshToken                types.String  `tfsdk:"refresh_token"`
+	ClientId                    types.String  `tfsdk:"client_id"`
+	ClientSecret                types.String  `tfsdk:"client_secret"`
+	TokenURL                    types.String  `tfsdk:"token_url"`
 }
 
 func (p *SanityProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -39,16 +67,132 @@ func (p *SanityProvider) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diag
 	return tfsdk.Schema{
 		Attributes: map[string]tfsdk.Attribute{
 			"token": {
-				MarkdownDescription: "The auth token used to authenticate with Sanity. May be sourced from the `SANITY_TOKEN` environment variable instead of via this attribute.",
+				MarkdownDescription: "The auth token used to authenticate with Sanity. May be sourced from the `SANITY_TOKEN` environment variable or from `token_file` instead of via this attribute, in that order of precedence.",
 				Optional:            true,
 				Computed:            true,
 				Sensitive:           true,
 				Type:                types.StringType,
 			},
+			"token_file": {
+				MarkdownDescription: "The path to a file that contains the auth token. It is only used when neither `token` nor the `SANITY_TOKEN` environment variable is set. Trailing whitespace and newlines in the file are ignored.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"fallback_token": {
+				MarkdownDescription: "An auth token that a request is sent again with when Sanity rejects the provider token with a `401`. It is meant for credential rotation windows: configure the new token as `token` and the old one as `fallback_token` (or the other way around) until every system has switched, then remove it. Requests that use the `token` of a resource are never sent with the fallback.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"use_cli_credentials": {
+				MarkdownDescription: "Indicates whether the settings that the Sanity CLI stores after `sanity login` are used for the settings that are not configured otherwise. They are read from `sanity/config.json` in `$XDG_CONFIG_HOME`, or in `~/.config` when that is not set. Its `authToken` is only used when neither `token`, the `SANITY_TOKEN` environment variable, nor `token_file` supply a token, and its `apiUrl` and `organization` only when `api_url` and `organization` and their environment variables are unset. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"refresh_token": {
+				MarkdownDescription: "An OAuth refresh token used to obtain short-lived access tokens, for setups where Sanity is accessed through single sign-on. Requires `client_id` and `token_url`. Configure either `refresh_token` or `token`/`token_file`, not both.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"client_id": {
+				MarkdownDescription: "The OAuth client ID used with `refresh_token`.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"client_secret": {
+				MarkdownDescription: "The OAuth client secret used with `refresh_token`, if the client has one.",
+				Optional:            true,
+				Sensitive:           true,
+				Type:                types.StringType,
+			},
+			"token_url": {
+				MarkdownDescription: "The URL of the OAuth token endpoint that `refresh_token` is exchanged at.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"api_url": {
+				MarkdownDescription: "The base URL of the Sanity API. Defaults to `https://api.sanity.io`. May be sourced from the `SANITY_API_URL` environment variable instead of via this attribute.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"proxy_url": {
+				MarkdownDescription: "The URL of an HTTP proxy that all requests to the Sanity API, including OAuth token refreshes, are sent through, such as `http://proxy.example.com:3128`. Without it, the `HTTP_PROXY`, `HTTPS_PROXY` and `NO_PROXY` environment variables are respected; setting it overrides them.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
+			"max_retries": {
+				MarkdownDescription: "The maximum number of times an idempotent request is retried after a rate limit (429) or server (5xx) error. Defaults to `3`.",
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"request_timeout": {
+				MarkdownDescription: "The number of seconds to wait for a request to the Sanity API to complete. A request that takes longer is abandoned and, like other transient errors, retried up to `max_retries` times. Defaults to `30`.",
+				Optional:            true,
+				Type:                types.Int64Type,
+			},
+			"requests_per_second": {
+				MarkdownDescription: "The maximum number of requests per second sent to the Sanity API. Regardless of this cap, requests are held back when the rate limit headers of the Sanity API show that the limit is nearly reached. Defaults to no cap.",
+				Optional:            true,
+				Type:                types.Float64Type,
+			},
+			"validate_token": {
+				MarkdownDescription: "Indicates whether the token is checked against the Sanity API when the provider is configured, so that an invalid token fails fast. Disable it to plan without network access. Defaults to `true`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"read_only": {
+				MarkdownDescription: "Indicates whether the provider refuses to create, update or delete anything in Sanity. Reads and data sources keep working, so `terraform plan` can run with a token that only has read access, for example in an audit pipeline, without any risk of an apply changing a project. Defaults to `false`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"default_cors_allow_credentials": {
+				MarkdownDescription: "The `allow_credentials` of a new `sanity_cors_origin` that does not set it. An origin that is allowed to send credentials can make authenticated requests on behalf of a logged-in user, so a site that is compromised or not fully trusted could read or change private content. Set this to `false` so that credentials have to be allowed explicitly, per origin. Changing it does not affect existing CORS origins. Defaults to `true`.",
+				Optional:            true,
+				Type:                types.BoolType,
+			},
+			"organization": {
+				MarkdownDescription: "The ID of the organization that new projects are created in when a `sanity_project` does not set its own `organization`. The `organization` of a project always takes precedence over this default. May be sourced from the `SANITY_ORGANIZATION` environment variable instead of via this attribute.",
+				Optional:            true,
+				Type:                types.StringType,
+			},
 		},
 	}, nil
 }
 
+// ValidateConfig fails early when no source of a token is configured, so that
+// `terraform validate` reports missing credentials instead of the first plan.
+// Values that are not known yet could supply a token and are not checked.
+func (p *SanityProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var config SanityProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.Token.Unknown || config.TokenFile.Unknown || config.RefreshToken.Unknown || config.UseCLICredentials.Unknown {
+		return
+	}
+
+	if !config.Token.Null && config.Token.Value == "" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Invalid token",
+			"The token cannot be an empty string. Remove the attribute to source the token from the SANITY_TOKEN environment variable or token_file instead.",
+		)
+		return
+	}
+
+	if config.Token.Null && config.TokenFile.Null && config.RefreshToken.Null && !config.UseCLICredentials.Value && os.Getenv("SANITY_TOKEN") == "" {
+		resp.Diagnostics.AddError(
+			"Missing credentials",
+			"No token is configured. Set token, token_file, or refresh_token in the provider configuration, set use_cli_credentials to use the token of the Sanity CLI, or set the SANITY_TOKEN environment variable.",
+		)
+	}
+}
+
 func (p *SanityProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var config SanityProviderModel
 
@@ -58,6 +202,40 @@ func (p *SanityProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if config.FallbackToken.Unknown {
+		resp.Diagnostics.AddWarning(
+			"Unable to create client",
+			"Cannot use unknown value as fallback_token",
+		)
+		return
+	}
+
+	if config.RefreshToken.Unknown || config.ClientId.Unknown || config.ClientSecret.Unknown || config.TokenURL.Unknown {
+		resp.Diagnostics.AddWarning(
+			"Unable to create client",
+			"Cannot use unknown values to configure the refresh token",
+		)
+		return
+	}
+
+	useRefreshToken := !config.RefreshToken.Null
+	if useRefreshToken && (!config.Token.Null || !config.TokenFile.Null) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("refresh_token"),
+			"Conflicting authentication methods",
+			"Configure either token or token_file, or refresh_token, but not both.",
+		)
+		return
+	}
+	if useRefreshToken && (config.ClientId.Null || config.TokenURL.Null) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("refresh_token"),
+			"Incomplete refresh token configuration",
+			"client_id and token_url are required when refresh_token is set.",
+		)
+		return
+	}
+
 	var token string
 	if config.Token.Unknown {
 		resp.Diagnostics.AddWarning(
@@ -73,7 +251,41 @@ func (p *SanityProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		token = config.Token.Value
 	}
 
-	if token == "" {
+	if token == "" && !config.TokenFile.Null && !config.TokenFile.Unknown {
+		b, err := os.ReadFile(config.TokenFile.Value)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token_file"),
+				"Unable to read token file",
+				fmt.Sprintf("The token could not be read from %s: %s", config.TokenFile.Value, err),
+			)
+			return
+		}
+		token = strings.TrimRight(string(b), " \t\r\n")
+	}
+
+	// the Sanity CLI configuration only supplies the settings that are not
+	// configured otherwise, so a missing file is only an error without a token
+	cli := &cliConfig{}
+	if config.UseCLICredentials.Value {
+		c, err := loadCLIConfig()
+		if err == nil {
+			cli = c
+		} else if token == "" && !useRefreshToken {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("use_cli_credentials"),
+				"Unable to read Sanity CLI credentials",
+				fmt.Sprintf("The token could not be read from the Sanity CLI configuration: %s. Run sanity login, or configure a token.", err),
+			)
+			return
+		}
+	}
+
+	if token == "" && !useRefreshToken {
+		token = cli.AuthToken
+	}
+
+	if token == "" && !useRefreshToken {
 		resp.Diagnostics.AddError(
 			"Unable to find token",
 			"Token cannot be an empty string",
@@ -81,12 +293,170 @@ func (p *SanityProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		return
 	}
 
+	if config.ApiURL.Unknown {
+		resp.Diagnostics.AddWarning(
+			"Unable to create client",
+			"Cannot use unknown value as api_url",
+		)
+		return
+	}
+
+	var apiURL string
+	if config.ApiURL.Null {
+		apiURL = os.Getenv("SANITY_API_URL")
+		if apiURL == "" {
+			apiURL = cli.ApiURL
+		}
+	} else {
+		apiURL = config.ApiURL.Value
+	}
+
+	if config.ProxyURL.Unknown {
+		resp.Diagnostics.AddWarning(
+			"Unable to create client",
+			"Cannot use unknown value as proxy_url",
+		)
+		return
+	}
+
+	// the default transport uses the proxy from the environment
+	var transport http.RoundTripper = http.DefaultTransport
+	if !config.ProxyURL.Null {
+		proxyURL, err := url.Parse(config.ProxyURL.Value)
+		if err == nil && (proxyURL.Scheme == "" || proxyURL.Host == "") {
+			err = fmt.Errorf("%q is not an absolute URL", config.ProxyURL.Value)
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy_url"),
+				"Invalid Proxy URL",
+				fmt.Sprintf("The proxy URL could not be parsed: %s", err),
+			)
+			return
+		}
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		transport = t
+	}
+
+	maxRetries := defaultMaxRetries
+	if !config.MaxRetries.Null && !config.MaxRetries.Unknown {
+		maxRetries = int(config.MaxRetries.Value)
+	}
+	if maxRetries < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("max_retries"),
+			"Invalid Max Retries",
+			"The maximum number of retries cannot be negative",
+		)
+		return
+	}
+
+	requestTimeout := defaultRequestTimeout
+	if !config.RequestTimeout.Null && !config.RequestTimeout.Unknown {
+		requestTimeout = time.Duration(config.RequestTimeout.Value) * time.Second
+	}
+	if requestTimeout <= 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("request_timeout"),
+			"Invalid Request Timeout",
+			"The request timeout must be a positive number of seconds",
+		)
+		return
+	}
+
+	requestsPerSecond := 0.0
+	if !config.RequestsPerSecond.Null && !config.RequestsPerSecond.Unknown {
+		requestsPerSecond = config.RequestsPerSecond.Value
+	}
+	if requestsPerSecond < 0 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("requests_per_second"),
+			"Invalid Requests Per Second",
+			"The number of requests per second cannot be negative",
+		)
+		return
+	}
+
+	if config.Organization.Unknown {
+		resp.Diagnostics.AddWarning(
+			"Unable to create client",
+			"Cannot use unknown value as organization",
+		)
+		return
+	}
+
+	var organization string
+	if config.Organization.Null {
+		organization = os.Getenv("SANITY_ORGANIZATION")
+		if organization == "" {
+			organization = cli.Organization
+		}
+	} else {
+		organization = config.Organization.Value
+	}
+
 	tokenSrc := oauth2.StaticTokenSource(
 		&oauth2.Token{AccessToken: token},
 	)
-	httpClient := oauth2.NewClient(context.Background(), tokenSrc)
+	if useRefreshToken {
+		oauthConfig := &oauth2.Config{
+			ClientID:     config.ClientId.Value,
+			ClientSecret: config.ClientSecret.Value,
+			Endpoint:     oauth2.Endpoint{TokenURL: config.TokenURL.Value},
+		}
+		// the token endpoint is reached through the same proxy as the API
+		oauthCtx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Transport: transport})
+		tokenSrc = oauthConfig.TokenSource(oauthCtx, &oauth2.Token{RefreshToken: config.RefreshToken.Value})
+	}
+	var base http.RoundTripper = &tokenOverrideTransport{next: transport}
+	if config.FallbackToken.Value != "" {
+		base = &fallbackTokenTransport{token: config.FallbackToken.Value, next: base}
+	}
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(nil, tokenSrc),
+			Base:   base,
+		},
+	}
+	httpClient.Transport = &userAgentTransport{userAgent: userAgent(p.version), next: httpClient.Transport}
+	httpClient.Transport = &retryTransport{maxRetries: maxRetries, timeout: requestTimeout, next: httpClient.Transport}
+	// the rate limiter waits outside of the retries, so that waiting for the
+	// rate limit to reset does not count against the timeout of an attempt
+	httpClient.Transport = &rateLimitTransport{limiter: newRateLimiter(requestsPerSecond), next: httpClient.Transport}
+
+	client, err := NewClient(httpClient, apiURL)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("api_url"),
+			"Invalid API URL",
+			fmt.Sprintf("The API URL could not be parsed: %s", err),
+		)
+		return
+	}
+	client.defaultOrganization = organization
+	client.readOnly = config.ReadOnly.Value
+	client.defaultCORSAllowCredentials = config.DefaultCORSAllowCredentials.Null || config.DefaultCORSAllowCredentials.Value
+
+	if config.ValidateToken.Null || config.ValidateToken.Value {
+		_, err = client.GetCurrentUser(ctx)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			resp.Diagnostics.AddError(
+				"Authentication failed",
+				fmt.Sprintf("Sanity did not accept the configured token: %s. Check the token, or set validate_token to false to skip this check.", apiErr),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Unable to validate token",
+				fmt.Sprintf("The token could not be checked against the Sanity API: %s. Set validate_token to false to skip this check.", err),
+			)
+			return
+		}
+	}
 
-	client := sanity.NewClient(httpClient)
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
@@ -97,12 +467,31 @@ func (p *SanityProvider) Resources(ctx context.Context) []func() resource.Resour
 		NewCORSOriginResource,
 		NewDatasetResource,
 		NewProjectTokenResource,
+		NewWebhookResource,
+		NewProjectMemberResource,
+		NewTagResource,
+		NewDatasetACLResource,
+		NewCORSOriginsResource,
 	}
 }
 
 func (p *SanityProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewProjectDataSource,
+		NewProjectsDataSource,
+		NewDatasetDataSource,
+		NewDatasetsDataSource,
+		NewCORSOriginDataSource,
+		NewCORSOriginsDataSource,
+		NewProjectRolesDataSource,
+		NewProjectTokenDataSource,
+		NewProjectTokensDataSource,
+		NewOrganizationsDataSource,
+		NewOrganizationDataSource,
+		NewCurrentUserDataSource,
+		NewGROQDataSource,
+		NewWebhookDataSource,
+		NewWebhooksDataSource,
 	}
 }
 
@@ -0,0 +1,799 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	sdkresource "github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"golang.org/x/oauth2"
+)
+
+// testAccProtoV6ProviderFactories are used to instantiate a provider during
+// testing. The factory function is invoked for every Terraform CLI command
+// executed to create a provider server to which the CLI can reattach.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"sanity": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// mockTestCase is a test case against a mock Sanity API. Each case starts with
+// an empty mock, which setup can fill before the steps run.
+type mockTestCase struct {
+	name  string
+	setup func(m *mockSanity)
+	steps func(m *mockSanity) []sdkresource.TestStep
+
+	// checkDestroy checks the mock after the resources were destroyed.
+	checkDestroy func(m *mockSanity) error
+}
+
+// runMockTestCases runs each case as a subtest with its own mock Sanity API.
+func runMockTestCases(t *testing.T, cases []mockTestCase) {
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			if tc.setup != nil {
+				tc.setup(m)
+			}
+
+			testCase := sdkresource.TestCase{
+				ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+				Steps:                    tc.steps(m),
+			}
+			if tc.checkDestroy != nil {
+				testCase.CheckDestroy = testCheckMock(func() error { return tc.checkDestroy(m) })
+			}
+
+			sdkresource.UnitTest(t, testCase)
+		})
+	}
+}
+
+// testCheckMock runs a check against the mock Sanity API as a step check.
+func testCheckMock(check func() error) sdkresource.TestCheckFunc {
+	return func(*terraform.State) error {
+		return check()
+	}
+}
+
+// testCheckRequestCount checks how many requests the mock received for the
+// method and path, which is matched like in mockSanity.fail.
+func testCheckRequestCount(m *mockSanity, method string, path string, want int) sdkresource.TestCheckFunc {
+	return testCheckMock(func() error {
+		if got := len(m.requestsTo(method, path)); got != want {
+			return fmt.Errorf("expected %d %s %s requests, got %d", want, method, path, got)
+		}
+		return nil
+	})
+}
+
+// client returns a client for the mock Sanity API that is authenticated like
+// the one the provider builds.
+func (m *mockSanity) client(t *testing.T) *Client {
+	t.Helper()
+
+	httpClient := &http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: mockToken}),
+		},
+	}
+	client, err := NewClient(httpClient, m.server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client
+}
+
+// resourceTest calls the methods of a resource directly with a client for the
+// mock Sanity API. The test harness of terraform-plugin-sdk requires every
+// resource to have an `id` attribute, so the resources without one are tested
+// this way. Plans are given as models and must include the values that plan
+// modifiers would set, such as defaults.
+type resourceTest struct {
+	t      *testing.T
+	r      resource.Resource
+	schema tfsdk.Schema
+}
+
+func newResourceTest(t *testing.T, m *mockSanity, r resource.Resource) *resourceTest {
+	t.Helper()
+
+	ctx := context.Background()
+	schema, diags := r.GetSchema(ctx)
+	requireNoDiagnostics(t, diags)
+
+	var resp resource.ConfigureResponse
+	r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: m.client(t)}, &resp)
+	requireNoDiagnostics(t, resp.Diagnostics)
+
+	return &resourceTest{t: t, r: r, schema: schema}
+}
+
+// state returns the state that holds the model.
+func (rt *resourceTest) state(model any) tfsdk.State {
+	rt.t.Helper()
+
+	state := tfsdk.State{Schema: rt.schema, Raw: rt.null()}
+	requireNoDiagnostics(rt.t, state.Set(context.Background(), model))
+	return state
+}
+
+// stateWith returns the state that holds the attributes and leaves the other
+// attributes null.
+func (rt *resourceTest) stateWith(attributes map[string]attr.Value) tfsdk.State {
+	rt.t.Helper()
+
+	state := tfsdk.State{Schema: rt.schema, Raw: rt.null()}
+	for name, value := range attributes {
+		requireNoDiagnostics(rt.t, state.SetAttribute(context.Background(), path.Root(name), value))
+	}
+	return state
+}
+
+func (rt *resourceTest) plan(model any) tfsdk.Plan {
+	state := rt.state(model)
+	return tfsdk.Plan{Schema: state.Schema, Raw: state.Raw}
+}
+
+func (rt *resourceTest) config(model any) tfsdk.Config {
+	state := rt.state(model)
+	return tfsdk.Config{Schema: state.Schema, Raw: state.Raw}
+}
+
+func (rt *resourceTest) null() tftypes.Value {
+	return tftypes.NewValue(rt.schema.Type().TerraformType(context.Background()), nil)
+}
+
+func (rt *resourceTest) create(plan any) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.CreateResponse{State: tfsdk.State{Schema: rt.schema, Raw: rt.null()}}
+	rt.r.Create(context.Background(), resource.CreateRequest{Config: rt.config(plan), Plan: rt.plan(plan)}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+func (rt *resourceTest) read(state tfsdk.State) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.ReadResponse{State: state}
+	rt.r.Read(context.Background(), resource.ReadRequest{State: state}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+func (rt *resourceTest) update(state tfsdk.State, plan any) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.UpdateResponse{State: state}
+	rt.r.Update(context.Background(), resource.UpdateRequest{Config: rt.config(plan), Plan: rt.plan(plan), State: state}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+func (rt *resourceTest) delete(state tfsdk.State) diag.Diagnostics {
+	resp := resource.DeleteResponse{State: state}
+	rt.r.Delete(context.Background(), resource.DeleteRequest{State: state}, &resp)
+	return resp.Diagnostics
+}
+
+func (rt *resourceTest) importState(id string) (tfsdk.State, diag.Diagnostics) {
+	resp := resource.ImportStateResponse{State: tfsdk.State{Schema: rt.schema, Raw: rt.null()}}
+	rt.r.(resource.ResourceWithImportState).ImportState(context.Background(), resource.ImportStateRequest{ID: id}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+// upgradeState calls the state upgrader for the version with the state in
+// JSON, as Terraform stores it. Like the framework, it fails to decode the
+// state if it does not match the prior schema of the upgrader.
+func (rt *resourceTest) upgradeState(version int64, rawState string) (tfsdk.State, diag.Diagnostics) {
+	rt.t.Helper()
+
+	ctx := context.Background()
+	upgrader, ok := rt.r.(resource.ResourceWithUpgradeState).UpgradeState(ctx)[version]
+	if !ok {
+		rt.t.Fatalf("expected a state upgrader for version %d", version)
+	}
+
+	raw, err := (&tfprotov6.RawState{JSON: []byte(rawState)}).Unmarshal(upgrader.PriorSchema.Type().TerraformType(ctx))
+	if err != nil {
+		rt.t.Fatalf("the state does not match the prior schema: %s", err)
+	}
+
+	req := resource.UpgradeStateRequest{State: &tfsdk.State{Schema: *upgrader.PriorSchema, Raw: raw}}
+	resp := resource.UpgradeStateResponse{State: tfsdk.State{Schema: rt.schema, Raw: rt.null()}}
+	upgrader.StateUpgrader(ctx, req, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+// modifyPlan calls ModifyPlan to go from the state to the plan. The state is
+// nil for a create.
+func (rt *resourceTest) modifyPlan(state *tfsdk.State, plan any) resource.ModifyPlanResponse {
+	req := resource.ModifyPlanRequest{Config: rt.config(plan), Plan: rt.plan(plan), State: tfsdk.State{Schema: rt.schema, Raw: rt.null()}}
+	if state != nil {
+		req.State = *state
+	}
+	resp := resource.ModifyPlanResponse{Plan: req.Plan}
+	rt.r.(resource.ResourceWithModifyPlan).ModifyPlan(context.Background(), req, &resp)
+	return resp
+}
+
+// stateModel returns the model held by the state.
+func stateModel[T any](t *testing.T, state tfsdk.State) T {
+	t.Helper()
+
+	var model T
+	requireNoDiagnostics(t, state.Get(context.Background(), &model))
+	return model
+}
+
+func requireNoDiagnostics(t *testing.T, diags diag.Diagnostics) {
+	t.Helper()
+
+	if diags.HasError() {
+		t.Fatalf("unexpected error diagnostics: %v", diags)
+	}
+}
+
+// requireErrorDiagnostic checks that the diagnostics have an error whose
+// summary or detail contains the text.
+func requireErrorDiagnostic(t *testing.T, diags diag.Diagnostics, text string) {
+	t.Helper()
+
+	for _, d := range diags.Errors() {
+		if strings.Contains(d.Summary(), text) || strings.Contains(d.Detail(), text) {
+			return
+		}
+	}
+	t.Fatalf("expected an error diagnostic containing %q, got: %v", text, diags)
+}
+
+func TestProvider_requestTimeout(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "a request that takes too long fails",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+				m.handle("GET", "/projects/p1", func(w http.ResponseWriter, r *http.Request) bool {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(10 * time.Second):
+					}
+					return true
+				})
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config: m.providerConfigWith(`request_timeout = 1`, `
+data "sanity_project" "test" {
+  id = "p1"
+}
+`),
+					ExpectError: regexp.MustCompile(`context deadline\s+exceeded`),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_rateLimitReset(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "waiting for the reset does not count against the request timeout",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+
+				// the first response uses up the rate limit until it resets, which is
+				// longer than the request timeout
+				var served int32
+				m.handle("GET", "/projects/p1", func(w http.ResponseWriter, r *http.Request) bool {
+					if atomic.AddInt32(&served, 1) == 1 {
+						w.Header().Set("X-RateLimit-Remaining", "0")
+						w.Header().Set("X-RateLimit-Reset", "2")
+					}
+					return false
+				})
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config: m.providerConfigWith(`request_timeout = 1`, `
+data "sanity_project" "first" {
+  id = "p1"
+}
+
+data "sanity_project" "second" {
+  id = data.sanity_project.first.id
+}
+`),
+					Check: sdkresource.TestCheckResourceAttr("data.sanity_project.second", "name", "Test"),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_requestsPerSecond(t *testing.T) {
+	config := `
+data "sanity_project" "test" {
+  id = "p1"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "valid",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config: m.providerConfigWith(`requests_per_second = 2.5`, config),
+					Check:  sdkresource.TestCheckResourceAttr("data.sanity_project.test", "name", "Test"),
+				}}
+			},
+		},
+		{
+			name: "negative",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config:      m.providerConfigWith(`requests_per_second = -1`, config),
+					ExpectError: regexp.MustCompile(`The number of requests per second cannot be negative`),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_credentials(t *testing.T) {
+	// config configures the provider without a token
+	config := func(m *mockSanity, attributes string) string {
+		return fmt.Sprintf(`
+provider "sanity" {
+  api_url     = %q
+  max_retries = 0
+%s
+}
+
+data "sanity_project" "test" {
+  id = "p1"
+}
+`, m.server.URL, attributes)
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "missing",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						t.Setenv("SANITY_TOKEN", "")
+					},
+					Config:      config(m, ""),
+					ExpectError: regexp.MustCompile(`No token is configured`),
+				}}
+			},
+		},
+		{
+			name: "empty token",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						t.Setenv("SANITY_TOKEN", mockToken)
+					},
+					Config:      config(m, `token = ""`),
+					ExpectError: regexp.MustCompile(`The token cannot be an empty string`),
+				}}
+			},
+		},
+		{
+			name: "from the environment",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						t.Setenv("SANITY_TOKEN", mockToken)
+					},
+					Config: config(m, ""),
+					Check:  sdkresource.TestCheckResourceAttr("data.sanity_project.test", "name", "Test"),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_cliCredentials(t *testing.T) {
+	// writeCLIConfig writes the configuration of the Sanity CLI to a temporary
+	// directory that is used as the XDG config directory
+	writeCLIConfig := func(t *testing.T, config string) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "sanity"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "sanity", "config.json"), []byte(config), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv("XDG_CONFIG_HOME", dir)
+		t.Setenv("SANITY_TOKEN", "")
+		t.Setenv("SANITY_API_URL", "")
+		t.Setenv("SANITY_ORGANIZATION", "")
+	}
+
+	project := `
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "all settings from the file",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						writeCLIConfig(t, fmt.Sprintf(`{"authToken": %q, "apiUrl": %q, "organization": "org2"}`, mockToken, m.server.URL))
+					},
+					Config: `
+provider "sanity" {
+  use_cli_credentials = true
+  max_retries         = 0
+}
+` + project,
+					Check: sdkresource.TestCheckResourceAttr("sanity_project.test", "organization", "org2"),
+				}}
+			},
+		},
+		{
+			name: "attributes take precedence",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						writeCLIConfig(t, `{"authToken": "sk-cli", "apiUrl": "http://127.0.0.1:1", "organization": "org2"}`)
+					},
+					Config: m.providerConfigWith(`
+  use_cli_credentials = true
+  organization        = "org1"
+`, project),
+					Check: sdkresource.TestCheckResourceAttr("sanity_project.test", "organization", "org1"),
+				}}
+			},
+		},
+		{
+			name: "the file is not needed with a token",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+					},
+					Config: m.providerConfigWith(`
+  use_cli_credentials = true
+`, project),
+					Check: sdkresource.TestCheckResourceAttr("sanity_project.test", "name", "Test"),
+				}}
+			},
+		},
+		{
+			name: "a missing file without a token",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					PreConfig: func() {
+						t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+						t.Setenv("SANITY_TOKEN", "")
+					},
+					Config: fmt.Sprintf(`
+provider "sanity" {
+  use_cli_credentials = true
+  api_url             = %q
+  max_retries         = 0
+}
+`, m.server.URL) + project,
+					ExpectError: regexp.MustCompile(`Unable to read Sanity CLI credentials`),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_fallbackToken(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "the fallback is used when the token is rejected",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config: fmt.Sprintf(`
+provider "sanity" {
+  token          = "sk-rotated"
+  fallback_token = %q
+  api_url        = %q
+  max_retries    = 0
+}
+
+resource "sanity_tag" "test" {
+  project = "p1"
+  name    = "release"
+}
+`, mockToken, m.server.URL),
+					Check: sdkresource.ComposeAggregateTestCheckFunc(
+						sdkresource.TestCheckResourceAttr("sanity_tag.test", "name", "release"),
+						testCheckMock(func() error {
+							for _, r := range m.requestsTo("POST", "/projects/p1/tags") {
+								if r.Header.Get("Authorization") == "Bearer "+mockToken && len(r.Body) > 0 {
+									return nil
+								}
+							}
+							return fmt.Errorf("expected the tag to be created with the fallback token")
+						}),
+					),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_validateToken(t *testing.T) {
+	// invalidTokenConfig configures the provider with a token that the mock
+	// does not accept
+	invalidTokenConfig := func(m *mockSanity, attributes string, config string) string {
+		return fmt.Sprintf(`
+provider "sanity" {
+  token       = "invalid"
+  api_url     = %q
+  max_retries = 0
+%s
+}
+`, m.server.URL, attributes) + config
+	}
+
+	project := `
+resource "sanity_project" "test" {
+  name = "Test"
+}
+`
+
+	dataSource := `
+data "sanity_project" "test" {
+  id = "p1"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "a valid token is accepted",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config: m.providerConfig(dataSource),
+					Check: testCheckMock(func() error {
+						if len(m.requestsTo("GET", "/users/me")) == 0 {
+							return fmt.Errorf("expected the token to be validated")
+						}
+						return nil
+					}),
+				}}
+			},
+		},
+		{
+			name: "an invalid token fails",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config:      invalidTokenConfig(m, "", project),
+					PlanOnly:    true,
+					ExpectError: regexp.MustCompile(`Authentication failed`),
+				}}
+			},
+		},
+		{
+			name: "validation can be turned off",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{
+					{
+						// planning a new project does not call the API
+						Config:             invalidTokenConfig(m, "validate_token = false", project),
+						PlanOnly:           true,
+						ExpectNonEmptyPlan: true,
+					},
+					{
+						Config: m.providerConfigWith("validate_token = false", dataSource),
+						Check:  testCheckRequestCount(m, "GET", "/users/me", 0),
+					},
+				}
+			},
+		},
+	})
+}
+
+func TestResourceDelete_notFound(t *testing.T) {
+	p1 := types.String{Value: "p1"}
+
+	cases := []struct {
+		name       string
+		resource   func() resource.Resource
+		attributes map[string]attr.Value
+	}{
+		{
+			name:     "project",
+			resource: NewProjectResource,
+			attributes: map[string]attr.Value{
+				"id":                  p1,
+				"delete_behavior":     types.String{Value: projectDeleteBehaviorDelete},
+				"deletion_protection": types.Bool{Value: false},
+			},
+		},
+		{
+			name:     "archived project",
+			resource: NewProjectResource,
+			attributes: map[string]attr.Value{
+				"id":                  p1,
+				"delete_behavior":     types.String{Value: projectDeleteBehaviorArchive},
+				"deletion_protection": types.Bool{Value: false},
+			},
+		},
+		{
+			name:       "dataset",
+			resource:   NewDatasetResource,
+			attributes: map[string]attr.Value{"project": p1, "name": types.String{Value: "production"}},
+		},
+		{
+			name:       "dataset ACL",
+			resource:   NewDatasetACLResource,
+			attributes: map[string]attr.Value{"project": p1, "dataset": types.String{Value: "production"}},
+		},
+		{
+			name:       "CORS origin",
+			resource:   NewCORSOriginResource,
+			attributes: map[string]attr.Value{"project": p1, "id": types.String{Value: "42"}},
+		},
+		{
+			name:       "project member",
+			resource:   NewProjectMemberResource,
+			attributes: map[string]attr.Value{"project": p1, "member_id": types.String{Value: "user1"}},
+		},
+		{
+			name:       "project token",
+			resource:   NewProjectTokenResource,
+			attributes: map[string]attr.Value{"project": p1, "id": types.String{Value: "tok1"}},
+		},
+		{
+			name:       "tag",
+			resource:   NewTagResource,
+			attributes: map[string]attr.Value{"project": p1, "id": types.String{Value: "tag1"}},
+		},
+		{
+			name:       "webhook",
+			resource:   NewWebhookResource,
+			attributes: map[string]attr.Value{"project": p1, "id": types.String{Value: "hook1"}},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			m := newMockSanity(t)
+			m.addProject("p1", "Test")
+			for _, method := range []string{"DELETE", "PATCH", "PUT"} {
+				m.fail(method, ".*", 404)
+			}
+
+			rt := newResourceTest(t, m, tc.resource())
+			requireNoDiagnostics(t, rt.delete(rt.stateWith(tc.attributes)))
+
+			var sent int
+			for _, method := range []string{"DELETE", "PATCH", "PUT"} {
+				sent += len(m.requestsTo(method, ".*"))
+			}
+			if sent == 0 {
+				t.Fatal("expected a delete request")
+			}
+		})
+	}
+}
+
+func TestProvider_userAgent(t *testing.T) {
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "requests identify the provider",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config: m.providerConfig(`
+data "sanity_project" "test" {
+  id = "p1"
+}
+`),
+					Check: testCheckMock(func() error {
+						requests := m.requestsTo("GET", "/projects/p1")
+						if len(requests) == 0 {
+							return fmt.Errorf("expected the project to be read")
+						}
+						ua := requests[0].Header.Get("User-Agent")
+						if !strings.Contains(ua, "terraform-provider-sanity/test") || !strings.Contains(ua, "terraform-plugin-framework/v") {
+							return fmt.Errorf("expected the provider and framework versions in the User-Agent, got %q", ua)
+						}
+						return nil
+					}),
+				}}
+			},
+		},
+	})
+}
+
+func TestProvider_proxyURL(t *testing.T) {
+	// config configures the provider with the proxy
+	config := func(m *mockSanity, proxyURL string) string {
+		return m.providerConfigWith(fmt.Sprintf(`
+  proxy_url = %q
+`, proxyURL), `
+resource "sanity_tag" "test" {
+  project = "p1"
+  name    = "release"
+}
+`)
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "requests go through the proxy",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				// the proxy forwards the requests to the mock and records them
+				var proxied int32
+				proxy := httptest.NewServer(&httputil.ReverseProxy{
+					Director: func(r *http.Request) {
+						atomic.AddInt32(&proxied, 1)
+					},
+				})
+				t.Cleanup(proxy.Close)
+
+				return []sdkresource.TestStep{{
+					Config: config(m, proxy.URL),
+					Check: sdkresource.ComposeAggregateTestCheckFunc(
+						sdkresource.TestCheckResourceAttr("sanity_tag.test", "name", "release"),
+						testCheckMock(func() error {
+							m.mu.Lock()
+							defer m.mu.Unlock()
+
+							if n := atomic.LoadInt32(&proxied); n == 0 || int(n) != len(m.requests) {
+								return fmt.Errorf("expected all %d requests to go through the proxy, got %d", len(m.requests), n)
+							}
+							return nil
+						}),
+					),
+				}}
+			},
+		},
+		{
+			name: "an invalid proxy URL",
+			steps: func(m *mockSanity) []sdkresource.TestStep {
+				return []sdkresource.TestStep{{
+					Config:      config(m, "proxy.example.com:3128"),
+					ExpectError: regexp.MustCompile(`The proxy URL could not be parsed`),
+				}}
+			},
+		},
+	})
+}
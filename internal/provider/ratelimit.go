@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// rateLimitMinRemaining is the number of requests left in the current rate
+// limit window at which requests are held back until the window resets.
+const rateLimitMinRemaining = 1
+
+// rateLimiter is a token bucket that spaces out requests. It is shared by all
+// the requests of the provider and is safe for concurrent use.
+type rateLimiter struct {
+	mu sync.Mutex
+
+	// rate is the number of requests per second, or 0 for no limit.
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	// pausedUntil is when the rate limit window of the Sanity API resets after
+	// it was nearly used up.
+	pausedUntil time.Time
+}
+
+func newRateLimiter(requestsPerSecond float64) *rateLimiter {
+	burst := math.Max(1, requestsPerSecond)
+	return &rateLimiter{
+		rate:   requestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before sending the
+// request.
+func (l *rateLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	if l.pausedUntil.After(now) {
+		wait = l.pausedUntil.Sub(now)
+	}
+
+	if l.rate > 0 {
+		// concurrent requests may reserve out of order, and an earlier time
+		// must not take tokens from the bucket
+		if now.After(l.last) {
+			l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+			l.last = now
+		}
+		l.tokens--
+		if l.tokens < 0 {
+			if w := time.Duration(-l.tokens / l.rate * float64(time.Second)); w > wait {
+				wait = w
+			}
+		}
+	}
+
+	return wait
+}
+
+// pause holds back requests until the time.
+func (l *rateLimiter) pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// rateLimitTransport throttles requests so that large applies stay within the
+// rate limits of the Sanity API instead of running into 429 responses. It
+// caps the request rate at `requests_per_second` and holds requests back when
+// the `X-RateLimit-Remaining` header shows that the limit is nearly reached.
+// It wraps the retryTransport, so a request takes a single token however often
+// it is retried, and the retries are spaced out by the retry delay instead.
+type rateLimitTransport struct {
+	limiter *rateLimiter
+	next    http.RoundTripper
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if wait := t.limiter.reserve(time.Now()); wait > 0 {
+		tflog.Debug(ctx, "throttling sanity request", map[string]interface{}{
+			"method": req.Method,
+			"url":    req.URL.String(),
+			"delay":  wait.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if until, ok := rateLimitReset(resp.Header, time.Now()); ok {
+		t.limiter.pause(until)
+	}
+
+	return resp, nil
+}
+
+// rateLimitReset returns when the rate limit window resets if the response
+// shows that it is nearly used up. The reset header may be given in seconds
+// from now or as a Unix timestamp.
+func rateLimitReset(header http.Header, now time.Time) (time.Time, bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > rateLimitMinRemaining {
+		return time.Time{}, false
+	}
+
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil || reset <= 0 {
+		return time.Time{}, false
+	}
+
+	// no rate limit window is longer than a day, so larger values are
+	// timestamps
+	if reset < 24*60*60 {
+		return now.Add(time.Duration(reset) * time.Second), true
+	}
+	return time.Unix(reset, 0), true
+}
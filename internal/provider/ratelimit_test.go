@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitReset(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	cases := []struct {
+		name      string
+		remaining string
+		reset     string
+		want      time.Time
+		wantOk    bool
+	}{
+		{name: "seconds from now", remaining: "1", reset: "30", want: now.Add(30 * time.Second), wantOk: true},
+		{name: "unix timestamp", remaining: "0", reset: "1700000060", want: time.Unix(1700000060, 0), wantOk: true},
+		{name: "requests remaining", remaining: "2", reset: "30"},
+		{name: "no headers"},
+		{name: "invalid remaining", remaining: "few", reset: "30"},
+		{name: "invalid reset", remaining: "0", reset: "soon"},
+		{name: "no reset", remaining: "0"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			header := http.Header{}
+			if tc.remaining != "" {
+				header.Set("X-RateLimit-Remaining", tc.remaining)
+			}
+			if tc.reset != "" {
+				header.Set("X-RateLimit-Reset", tc.reset)
+			}
+
+			got, ok := rateLimitReset(header, now)
+			if ok != tc.wantOk || !got.Equal(tc.want) {
+				t.Fatalf("expected %s, %t, got %s, %t", tc.want, tc.wantOk, got, ok)
+			}
+		})
+	}
+}
+
+func TestRateLimiter_reserve(t *testing.T) {
+	start := time.Now()
+	l := newRateLimiter(2)
+
+	// the burst is sent right away
+	for i := 0; i < 2; i++ {
+		if wait := l.reserve(start); wait != 0 {
+			t.Fatalf("expected request %d to be sent right away, got a wait of %s", i+1, wait)
+		}
+	}
+
+	if wait := l.reserve(start); wait != 500*time.Millisecond {
+		t.Fatalf("expected the third request to wait 500ms, got %s", wait)
+	}
+	if wait := l.reserve(start); wait != time.Second {
+		t.Fatalf("expected the fourth request to wait 1s, got %s", wait)
+	}
+
+	// the bucket refills over time
+	if wait := l.reserve(start.Add(3 * time.Second)); wait != 0 {
+		t.Fatalf("expected a request after the bucket refilled to be sent right away, got a wait of %s", wait)
+	}
+}
+
+func TestRateLimiter_noLimit(t *testing.T) {
+	now := time.Now()
+	l := newRateLimiter(0)
+
+	for i := 0; i < 100; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Fatalf("expected no wait without a limit, got %s", wait)
+		}
+	}
+
+	l.pause(now.Add(time.Minute))
+	if wait := l.reserve(now); wait != time.Minute {
+		t.Fatalf("expected the pause to apply without a limit, got %s", wait)
+	}
+}
+
+func TestRateLimitTransport(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "1")
+		} else {
+			w.Header().Set("X-RateLimit-Remaining", "99")
+			w.Header().Set("X-RateLimit-Reset", "60")
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &rateLimitTransport{limiter: newRateLimiter(0), next: http.DefaultTransport}}
+
+	get := func() time.Duration {
+		t.Helper()
+
+		start := time.Now()
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return time.Since(start)
+	}
+
+	get()
+	if elapsed := get(); elapsed < 500*time.Millisecond {
+		t.Fatalf("expected the request after the limit was reached to wait for the reset, took %s", elapsed)
+	}
+	if elapsed := get(); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected the request after the reset to be sent right away, took %s", elapsed)
+	}
+}
+
+func TestRateLimitTransport_requestsPerSecond(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &rateLimitTransport{limiter: newRateLimiter(4), next: http.DefaultTransport}}
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	// the first 4 requests are the burst, and the other 2 are sent 250ms apart
+	if elapsed := time.Since(start); elapsed < 450*time.Millisecond {
+		t.Fatalf("expected the requests to be throttled, took %s", elapsed)
+	}
+}
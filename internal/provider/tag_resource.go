@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tessellator/go-sanity/sanity"
+)
+
+var _ resource.Resource = &TagResource{}
+var _ resource.ResourceWithImportState = &TagResource{}
+
+func NewTagResource() resource.Resource {
+	return &TagResource{}
+}
+
+type TagResource struct {
+	client *Client
+}
+
+type TagResourceModel struct {
+	Id      types.String `tfsdk:"id"`
+	Project types.String `tfsdk:"project"`
+	Name    types.String `tfsdk:"name"`
+	Title   types.String `tfsdk:"title"`
+	Token   types.String `tfsdk:"token"`
+}
+
+func (r *TagResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tag"
+}
+
+func (r *TagResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Provides a tag in a Sanity project. Tags can be assigned to datasets to organize them.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this tag instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
+			"id": {
+				Computed:            true,
+				MarkdownDescription: "The tag identifier used by Sanity. This is the same as the tag name.",
+				Type:                types.StringType,
+			},
+			"project": {
+				Required:            true,
+				MarkdownDescription: "The ID of the project that the tag belongs to.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"name": {
+				Required:            true,
+				MarkdownDescription: "The name of the tag. Changing the name renames the tag in place.",
+				Type:                types.StringType,
+			},
+			"title": {
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "A display-friendly label for the tag. Defaults to the tag name.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+			},
+		},
+	}, nil
+}
+
+func (r *TagResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *TagResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *TagResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity tag", map[string]interface{}{"project": data.Project.Value, "name": data.Name.Value})
+
+	title := data.Name.Value
+	if !data.Title.Null && !data.Title.Unknown {
+		title = data.Title.Value
+	}
+
+	tag, err := r.client.Projects.CreateDatasetTag(ctx, data.Project.Value, &sanity.CreateDatasetTagRequest{
+		Name:  data.Name.Value,
+		Title: title,
+	})
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Id = types.String{Value: tag.Name}
+	data.Name = types.String{Value: tag.Name}
+	data.Title = types.String{Value: tag.Title}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *TagResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity tag", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	if data.Id.Null {
+		resp.Diagnostics.AddError("Tag id is null", "Tag id is null")
+		return
+	}
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	tag, err := r.client.GetTag(ctx, data.Project.Value, data.Id.Value)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Id = types.String{Value: tag.Name}
+	data.Name = types.String{Value: tag.Name}
+	data.Title = types.String{Value: tag.Title}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *TagResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity tag", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	// the tag is addressed by its current name, which is the prior id
+	var tagId string
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("id"), &tagId)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	editReq := &sanity.EditDatasetTagRequest{
+		Name: data.Name.Value,
+	}
+	if !data.Title.Null && !data.Title.Unknown {
+		editReq.Title = data.Title.Value
+	}
+
+	tag, err := r.client.Projects.EditDatasetTag(ctx, data.Project.Value, tagId, editReq)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.Id = types.String{Value: tag.Name}
+	data.Name = types.String{Value: tag.Name}
+	data.Title = types.String{Value: tag.Title}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *TagResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *TagResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity tag", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	if data.Id.Null {
+		resp.Diagnostics.AddError("Tag id is null", "Tag id is null")
+		return
+	}
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	_, err := r.client.Projects.DeleteDatasetTag(ctx, data.Project.Value, data.Id.Value)
+
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "tag %s could not be deleted", data.Id.Value))
+		return
+	}
+}
+
+func (r *TagResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectId, tagId, _ := strings.Cut(req.ID, "/")
+	if projectId == "" || tagId == "" {
+		resp.Diagnostics.AddError("Import Error", "The format for importing a tag is project-id/tag-id")
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), resource.ImportStateRequest{ID: tagId}, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), resource.ImportStateRequest{ID: projectId}, resp)
+}
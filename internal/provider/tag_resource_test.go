@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestTagResource(t *testing.T) {
+	config := `
+resource "sanity_tag" "test" {
+  project = "p1"
+  name    = "content"
+  title   = "Content"
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "externally deleted",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config),
+						Check:  resource.TestCheckResourceAttr("sanity_tag.test", "id", "content"),
+					},
+					{
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.tags = nil
+							})
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+					},
+					{
+						Config: m.providerConfig(config),
+						Check:  testCheckRequestCount(m, "POST", "/projects/p1/tags", 2),
+					},
+				}
+			},
+		},
+	})
+}
@@ -0,0 +1,328 @@
+package provider
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// baseURLTransport redirects requests aimed at the production Sanity API to
+// another base URL. go-sanity does not allow its base URL to be configured, so
+// this is how its requests are pointed at the configured `api_url`.
+type baseURLTransport struct {
+	baseURL *url.URL
+	next    http.RoundTripper
+}
+
+func (t *baseURLTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host != defaultBaseURLHost {
+		return t.next.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request it was given
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.baseURL.Scheme
+	r.URL.Host = t.baseURL.Host
+	r.URL.Path = t.baseURL.Path + r.URL.Path
+	r.Host = ""
+
+	return t.next.RoundTrip(r)
+}
+
+// userAgentTransport appends the provider to the `User-Agent` header, so that
+// Sanity can tell the requests made by this provider apart.
+type userAgentTransport struct {
+	userAgent string
+	next      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ua := t.userAgent
+	if v := req.Header.Get("User-Agent"); v != "" {
+		ua = v + " " + ua
+	}
+
+	// a RoundTripper must not modify the request it was given
+	r := req.Clone(req.Context())
+	r.Header.Set("User-Agent", ua)
+
+	return t.next.RoundTrip(r)
+}
+
+// userAgent returns the `User-Agent` product tokens of the provider and the
+// version of terraform-plugin-framework it was built with.
+func userAgent(version string) string {
+	ua := "terraform-provider-sanity/" + version
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/hashicorp/terraform-plugin-framework" {
+				ua += " terraform-plugin-framework/" + dep.Version
+				break
+			}
+		}
+	}
+
+	return ua
+}
+
+type tokenContextKey struct{}
+
+// contextWithToken returns a context whose requests are authenticated with
+// `token` instead of the provider token. The context is returned unchanged when
+// the token is not set.
+func contextWithToken(ctx context.Context, token types.String) context.Context {
+	if token.Null || token.Unknown || token.Value == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, tokenContextKey{}, token.Value)
+}
+
+// tokenOverrideTransport authenticates requests with the token stored in
+// their context by contextWithToken. It must sit below the oauth2 transport so
+// that it replaces the provider token rather than being replaced by it.
+type tokenOverrideTransport struct {
+	next http.RoundTripper
+}
+
+func (t *tokenOverrideTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, ok := req.Context().Value(tokenContextKey{}).(string)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+
+	// a RoundTripper must not modify the request it was given
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	return t.next.RoundTrip(r)
+}
+
+// fallbackTokenTransport sends a request again with a fallback token when the
+// provider token is rejected with a 401, so that requests keep working while a
+// token is being rotated. It must sit below the oauth2 transport so that it
+// replaces the provider token. Requests authenticated with a token override,
+// and requests whose body cannot be replayed, are not sent again.
+type fallbackTokenTransport struct {
+	token string
+	next  http.RoundTripper
+}
+
+func (t *fallbackTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, ok := req.Context().Value(tokenContextKey{}).(string); ok {
+		return t.next.RoundTrip(req)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || (req.Body != nil && req.GetBody == nil) {
+		return resp, err
+	}
+
+	// a RoundTripper must not modify the request it was given
+	r := req.Clone(req.Context())
+	if req.Body != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		r.Body = body
+	}
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	resp.Body.Close()
+
+	tflog.Warn(req.Context(), "sanity rejected the provider token, retrying with the fallback token", map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	})
+
+	return t.next.RoundTrip(r)
+}
+
+// apiErrorTransport turns error responses from the Sanity API into an
+// *APIError. go-sanity only reports the message of a failed request, so this
+// is how the status code reaches the resources. The error is wrapped in a
+// *url.Error by the http package and can be detected with errors.As.
+type apiErrorTransport struct {
+	next http.RoundTripper
+}
+
+func (t *apiErrorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || resp.StatusCode < 300 {
+		return resp, err
+	}
+
+	defer resp.Body.Close()
+	return nil, newAPIError(resp)
+}
+
+// loggingTransport logs every request sent to the Sanity API and the status
+// of its response, which is what `TF_LOG=DEBUG` shows. Only the method, URL
+// and status are logged: headers and bodies can contain tokens and are left
+// out.
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}
+	tflog.Debug(ctx, "sending sanity request", fields)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	fields["duration"] = time.Since(start).String()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "sanity request failed", fields)
+		return resp, err
+	}
+
+	fields["status"] = resp.StatusCode
+	tflog.Debug(ctx, "received sanity response", fields)
+	return resp, nil
+}
+
+const (
+	retryBaseDelay = 1 * time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// retryTransport retries idempotent requests that fail with a transient error
+// (a 429 or 5xx response, or a network error). The delay between attempts
+// honors the `Retry-After` header, up to retryMaxDelay, and otherwise backs off
+// exponentially. Each attempt is abandoned after `timeout`, which counts as a
+// transient error, so that a stuck request is retried rather than failing the
+// whole call.
+type retryTransport struct {
+	maxRetries int
+	timeout    time.Duration
+	next       http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, t.timeout)
+
+		// a RoundTripper must not modify the request it was given
+		r := req.Clone(attemptCtx)
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(r)
+		if err != nil {
+			cancel()
+		} else {
+			// the deadline also applies to reading the body, so the attempt
+			// ends when the body is closed
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+		}
+		if attempt >= t.maxRetries || !isIdempotent(req) || !isRetryable(resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(resp, attempt)
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.String(),
+			"attempt": attempt + 1,
+			"delay":   delay.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			resp.Body.Close()
+		}
+		tflog.Warn(ctx, "retrying sanity request", fields)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// cancelOnCloseBody cancels the context of a request when its response body
+// is closed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// isIdempotent reports whether the request may safely be sent more than once.
+// Requests with a body that cannot be replayed are never retried.
+func isIdempotent(req *http.Request) bool {
+	if req.Body != nil && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryDelay returns how long to wait before the next attempt. The
+// `Retry-After` header is capped at retryMaxDelay like the backoff, so that a
+// server cannot hold a run for an hour per attempt.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	delay := time.Duration(float64(retryBaseDelay) * math.Pow(2, float64(attempt)))
+	if resp != nil {
+		if v := resp.Header.Get("Retry-After"); v != "" {
+			if seconds, err := strconv.Atoi(v); err == nil {
+				delay = time.Duration(seconds) * time.Second
+			} else if t, err := http.ParseTime(v); err == nil {
+				delay = time.Until(t)
+			}
+		}
+	}
+
+	if delay > retryMaxDelay {
+		return retryMaxDelay
+	}
+	return delay
+}
+
+// transportOrDefault returns the transport of the client, falling back to the
+// default transport as the http package does.
+func transportOrDefault(t http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return http.DefaultTransport
+	}
+	return t
+}
@@ -0,0 +1,216 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// slowServer starts a server whose first `slow` responses take longer than
+// any test timeout.
+func slowServer(t *testing.T, slow int32) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= slow {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			return
+		}
+		_, _ = io.WriteString(w, `{}`)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestRetryTransport_timeout(t *testing.T) {
+	server, calls := slowServer(t, 1)
+
+	client := &http.Client{Transport: &retryTransport{maxRetries: 0, timeout: 50 * time.Millisecond, next: http.DefaultTransport}}
+
+	start := time.Now()
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a context deadline error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the request to be abandoned after the timeout, took %s", elapsed)
+	}
+	if n := atomic.LoadInt32(calls); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+}
+
+func TestRetryTransport_timeoutIsRetried(t *testing.T) {
+	server, calls := slowServer(t, 1)
+
+	client := &http.Client{Transport: &retryTransport{maxRetries: 1, timeout: 50 * time.Millisecond, next: http.DefaultTransport}}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	// the body can still be read after RoundTrip returned
+	body, err := io.ReadAll(resp.Body)
+	if err != nil || string(body) != "{}" {
+		t.Fatalf("expected the body of the retried request, got %q, %v", body, err)
+	}
+	if n := atomic.LoadInt32(calls); n != 2 {
+		t.Fatalf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestUserAgentTransport(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: &userAgentTransport{userAgent: "terraform-provider-sanity/1.0.0", next: http.DefaultTransport}}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("User-Agent", "go-sanity")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got != "go-sanity terraform-provider-sanity/1.0.0" {
+		t.Fatalf("expected the provider to be appended to the User-Agent, got %q", got)
+	}
+	if req.Header.Get("User-Agent") != "go-sanity" {
+		t.Fatal("expected the original request to be left unchanged")
+	}
+}
+
+func TestFallbackTokenTransport(t *testing.T) {
+	type request struct {
+		token string
+		body  string
+	}
+
+	cases := []struct {
+		name string
+		// send sends a request to the URL with the client
+		send func(t *testing.T, client *http.Client, url string) (*http.Response, error)
+		want []request
+	}{
+		{
+			name: "the primary token is accepted",
+			send: func(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+				req, _ := http.NewRequest(http.MethodGet, url+"/ok", nil)
+				req.Header.Set("Authorization", "Bearer sk-primary")
+				return client.Do(req)
+			},
+			want: []request{{token: "sk-primary"}},
+		},
+		{
+			name: "the primary token is rejected",
+			send: func(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+				req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(`{"name":"test"}`))
+				req.Header.Set("Authorization", "Bearer sk-primary")
+				return client.Do(req)
+			},
+			want: []request{{token: "sk-primary", body: `{"name":"test"}`}, {token: "sk-fallback", body: `{"name":"test"}`}},
+		},
+		{
+			name: "a token override is not replaced",
+			send: func(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+				ctx := contextWithToken(context.Background(), types.String{Value: "sk-resource"})
+				req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+				req.Header.Set("Authorization", "Bearer sk-resource")
+				return client.Do(req)
+			},
+			want: []request{{token: "sk-resource"}},
+		},
+		{
+			name: "a body that cannot be replayed is not sent again",
+			send: func(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+				req, _ := http.NewRequest(http.MethodPost, url, io.NopCloser(strings.NewReader(`{}`)))
+				req.Header.Set("Authorization", "Bearer sk-primary")
+				return client.Do(req)
+			},
+			want: []request{{token: "sk-primary", body: `{}`}},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+				got = append(got, request{token: token, body: string(body)})
+				if token == "sk-primary" && r.URL.Path != "/ok" {
+					w.WriteHeader(http.StatusUnauthorized)
+				}
+			}))
+			t.Cleanup(server.Close)
+
+			client := &http.Client{Transport: &fallbackTokenTransport{token: "sk-fallback", next: http.DefaultTransport}}
+
+			resp, err := tc.send(t, client, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("expected requests %+v, got %+v", tc.want, got)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("expected requests %+v, got %+v", tc.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cases := []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		{name: "backoff", attempt: 2, want: 4 * time.Second},
+		{name: "backoff is capped", attempt: 10, want: retryMaxDelay},
+		{name: "retry after seconds", retryAfter: "5", want: 5 * time.Second},
+		{name: "retry after is capped", retryAfter: "3600", want: retryMaxDelay},
+		{name: "retry after date is capped", retryAfter: time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), want: retryMaxDelay},
+		{name: "invalid retry after", retryAfter: "soon", attempt: 1, want: 2 * time.Second},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+
+			if got := retryDelay(resp, tc.attempt); got != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
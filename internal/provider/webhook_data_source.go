@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &WebhookDataSource{}
+
+func NewWebhookDataSource() datasource.DataSource {
+	return &WebhookDataSource{}
+}
+
+// WebhookDataSource defines the data source implementation.
+type WebhookDataSource struct {
+	client *Client
+}
+
+// WebhookDataSourceModel describes the data source data model.
+type WebhookDataSourceModel struct {
+	Project    types.String `tfsdk:"project"`
+	Id         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	URL        types.String `tfsdk:"url"`
+	Dataset    types.String `tfsdk:"dataset"`
+	IsDisabled types.Bool   `tfsdk:"is_disabled"`
+}
+
+func (d *WebhookDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhook"
+}
+
+func (d *WebhookDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	attributes := webhookSummaryAttributes()
+	attributes["project"] = tfsdk.Attribute{
+		MarkdownDescription: "The ID of the project that the webhook belongs to.",
+		Type:                types.StringType,
+		Required:            true,
+	}
+	attributes["name"] = tfsdk.Attribute{
+		MarkdownDescription: "The name of the webhook. The name must match exactly one webhook in the project.",
+		Type:                types.StringType,
+		Required:            true,
+	}
+
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets a Sanity webhook by its name. The webhook secret is never returned.",
+		Attributes:          attributes,
+	}, nil
+}
+
+func (d *WebhookDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *WebhookDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WebhookDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	webhooks, err := d.client.ListWebhooks(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	var matches []Webhook
+	for _, w := range webhooks {
+		if w.Name == data.Name.Value {
+			matches = append(matches, w)
+		}
+	}
+
+	if len(matches) == 0 {
+		resp.Diagnostics.AddError("webhook not found", fmt.Sprintf("No webhook named %q was found in project %s", data.Name.Value, data.Project.Value))
+		return
+	}
+	if len(matches) > 1 {
+		resp.Diagnostics.AddError("multiple webhooks found", fmt.Sprintf("%d webhooks named %q were found in project %s. Give the webhooks distinct names to look one up.", len(matches), data.Name.Value, data.Project.Value))
+		return
+	}
+
+	webhook := matches[0]
+
+	data.Id = types.String{Value: webhook.Id}
+	data.URL = types.String{Value: webhook.URL}
+	data.Dataset = types.String{Value: webhook.Dataset}
+	data.IsDisabled = types.Bool{Value: webhook.IsDisabledByUser}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,568 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_plan_modifier"
+	"github.com/tessellator/terraform-provider-sanity/internal/provider/attribute_validator"
+)
+
+var _ resource.Resource = &WebhookResource{}
+var _ resource.ResourceWithImportState = &WebhookResource{}
+var _ resource.ResourceWithValidateConfig = &WebhookResource{}
+
+var webhookHttpMethodRegexp = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE)$`)
+
+func NewWebhookResource() resource.Resource {
+	return &WebhookResource{}
+}
+
+type WebhookResource struct {
+	client *Client
+}
+
+type WebhookResourceModel struct {
+	Id               types.String       `tfsdk:"id"`
+	Project          types.String       `tfsdk:"project"`
+	Type             types.String       `tfsdk:"type"`
+	Name             types.String       `tfsdk:"name"`
+	URL              types.String       `tfsdk:"url"`
+	Dataset          types.String       `tfsdk:"dataset"`
+	Description      types.String       `tfsdk:"description"`
+	IsDisabledByUser types.Bool         `tfsdk:"is_disabled_by_user"`
+	ApiVersion       types.String       `tfsdk:"api_version"`
+	HttpMethod       types.String       `tfsdk:"http_method"`
+	Headers          types.Map          `tfsdk:"headers"`
+	Secret           types.String       `tfsdk:"secret"`
+	Rule             []WebhookRuleModel `tfsdk:"rule"`
+	Token            types.String       `tfsdk:"token"`
+}
+
+type WebhookRuleModel struct {
+	On         types.List   `tfsdk:"on"`
+	Filter     types.String `tfsdk:"filter"`
+	Projection types.String `tfsdk:"projection"`
+}
+
+func (r *WebhookResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhook"
+}
+
+func (r *WebhookResource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Provides a Sanity webhook. A webhook sends an HTTP request to a URL whenever content in a dataset changes.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"token": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An auth token used to manage this webhook instead of the provider `token`, so that the resource can run with only the privileges it needs. Falls back to the provider token when unset.",
+				Type:                types.StringType,
+			},
+			"id": {
+				Computed:            true,
+				MarkdownDescription: "The unique webhook ID generated by Sanity.",
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.UseStateForUnknown(),
+				},
+				Type: types.StringType,
+			},
+			"project": {
+				Required:            true,
+				MarkdownDescription: "The ID of the project that the webhook belongs to.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"type": {
+				Required:            true,
+				MarkdownDescription: "The webhook type. Valid options are `document` and `transaction`. Document webhooks require a `rule` block, and transaction webhooks do not support one.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					resource.RequiresReplace(),
+				},
+			},
+			"name": {
+				Required:            true,
+				MarkdownDescription: "The webhook name.",
+				Type:                types.StringType,
+			},
+			"url": {
+				Required:            true,
+				MarkdownDescription: "The URL that receives the webhook requests.",
+				Type:                types.StringType,
+			},
+			"dataset": {
+				Required:            true,
+				MarkdownDescription: "The name of the dataset the webhook listens to. Use `*` to listen to all datasets.",
+				Type:                types.StringType,
+			},
+			"description": {
+				Optional:            true,
+				MarkdownDescription: "A short text describing the webhook.",
+				Type:                types.StringType,
+			},
+			"is_disabled_by_user": {
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Indicates whether the webhook is disabled. Defaults to `false`.",
+				Type:                types.BoolType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.Bool{Value: false}),
+				},
+			},
+			"api_version": {
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The API version used to evaluate the rule filter and projection. Defaults to `v2021-03-25`.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.String{Value: "v2021-03-25"}),
+				},
+			},
+			"http_method": {
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The HTTP method of the webhook requests. Valid options are `GET`, `POST`, `PUT`, `PATCH`, and `DELETE`. Defaults to `POST`.",
+				Type:                types.StringType,
+				PlanModifiers: tfsdk.AttributePlanModifiers{
+					attribute_plan_modifier.DefaultValue(types.String{Value: "POST"}),
+				},
+				Validators: []tfsdk.AttributeValidator{
+					attribute_validator.StringMatches(webhookHttpMethodRegexp, "The HTTP method must be one of GET, POST, PUT, PATCH, or DELETE"),
+				},
+			},
+			"headers": {
+				Optional:            true,
+				MarkdownDescription: "Additional HTTP headers sent with the webhook requests.",
+				Type:                types.MapType{ElemType: types.StringType},
+			},
+			"secret": {
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "A secret used to sign the webhook requests, so that the receiver can verify that they come from Sanity. Sanity does not return the secret, so changes made outside of Terraform are not detected.",
+				Type:                types.StringType,
+			},
+		},
+
+		Blocks: map[string]tfsdk.Block{
+			"rule": {
+				MarkdownDescription: "Describes which document changes trigger the webhook. Required on `document` webhooks and not supported on `transaction` webhooks.",
+				NestingMode:         tfsdk.BlockNestingModeList,
+				MaxItems:            1,
+				Attributes: map[string]tfsdk.Attribute{
+					"on": {
+						Required:            true,
+						MarkdownDescription: "The operations that trigger the webhook. Valid options are `create`, `update`, and `delete`.",
+						Type:                types.ListType{ElemType: types.StringType},
+					},
+					"filter": {
+						Optional:            true,
+						MarkdownDescription: "A GROQ filter that documents must match to trigger the webhook.",
+						Type:                types.StringType,
+					},
+					"projection": {
+						Optional:            true,
+						MarkdownDescription: "A GROQ projection that shapes the webhook payload.",
+						Type:                types.StringType,
+					},
+				},
+			},
+		},
+	}, nil
+}
+
+func (r *WebhookResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data WebhookResourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Type.Unknown || data.Type.Null {
+		return
+	}
+
+	switch data.Type.Value {
+	case WebhookTypeDocument:
+		if len(data.Rule) == 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rule"),
+				"Invalid Webhook Configuration",
+				"A document webhook requires a rule block that lists the operations that trigger it.",
+			)
+			return
+		}
+		resp.Diagnostics.Append(validateWebhookOn(data.Rule[0].On)...)
+	case WebhookTypeTransaction:
+		// a transaction webhook fires for every transaction, so it cannot
+		// filter or project documents
+		if len(data.Rule) > 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("rule"),
+				"Invalid Webhook Configuration",
+				"A rule cannot be set on a transaction webhook, including its filter and projection.",
+			)
+		}
+	default:
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Invalid Webhook Configuration",
+			fmt.Sprintf("The webhook type must be %q or %q, got: %q.", WebhookTypeDocument, WebhookTypeTransaction, data.Type.Value),
+		)
+	}
+}
+
+// validateWebhookOn checks the operations of a document webhook rule. Unknown
+// operations are checked once they are known.
+func validateWebhookOn(on types.List) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if on.Null || on.Unknown {
+		return diags
+	}
+
+	onPath := path.Root("rule").AtListIndex(0).AtName("on")
+
+	if len(on.Elems) == 0 {
+		diags.AddAttributeError(
+			onPath,
+			"Invalid Webhook Configuration",
+			"A document webhook must be triggered by at least one of \"create\", \"update\", or \"delete\".",
+		)
+		return diags
+	}
+
+	seen := make(map[string]bool, len(on.Elems))
+	for i, elem := range on.Elems {
+		op, ok := elem.(types.String)
+		if !ok || op.Null || op.Unknown {
+			continue
+		}
+
+		switch op.Value {
+		case "create", "update", "delete":
+		default:
+			diags.AddAttributeError(
+				onPath.AtListIndex(i),
+				"Invalid Webhook Configuration",
+				fmt.Sprintf("The operation must be \"create\", \"update\", or \"delete\", got: %q.", op.Value),
+			)
+			continue
+		}
+
+		if seen[op.Value] {
+			diags.AddAttributeError(
+				onPath.AtListIndex(i),
+				"Invalid Webhook Configuration",
+				fmt.Sprintf("The operation %q is listed more than once.", op.Value),
+			)
+		}
+		seen[op.Value] = true
+	}
+
+	return diags
+}
+
+func (r *WebhookResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	r.client = client
+}
+
+func (r *WebhookResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *WebhookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "creating sanity webhook", map[string]interface{}{"project": data.Project.Value, "name": data.Name.Value})
+
+	webhookReq, diags := data.toWebhook(ctx, nil)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	webhook, err := r.client.CreateWebhook(ctx, data.Project.Value, webhookReq)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.fromWebhook(webhook)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WebhookResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data *WebhookResourceModel
+
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "reading sanity webhook", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	if data.Id.Null {
+		resp.Diagnostics.AddError("Webhook id is null", "Webhook id is null")
+		return
+	}
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	webhook, err := r.client.GetWebhook(ctx, data.Project.Value, data.Id.Value)
+	if isNotFound(err) {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.fromWebhook(webhook)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WebhookResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data, state *WebhookResourceModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "updating sanity webhook", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	if data.Id.Null {
+		resp.Diagnostics.AddError("Webhook id is null", "Webhook id is null")
+		return
+	}
+
+	webhookReq, diags := data.toWebhook(ctx, state)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	webhook, err := r.client.UpdateWebhook(ctx, data.Project.Value, data.Id.Value, webhookReq)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	data.fromWebhook(webhook)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (r *WebhookResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	if r.client.readOnly {
+		resp.Diagnostics.Append(readOnlyDiagnostic())
+		return
+	}
+
+	var data *WebhookResourceModel
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = contextWithToken(ctx, data.Token)
+	tflog.Debug(ctx, "deleting sanity webhook", map[string]interface{}{"project": data.Project.Value, "id": data.Id.Value})
+
+	if data.Id.Null {
+		resp.Diagnostics.AddError("Webhook id is null", "Webhook id is null")
+		return
+	}
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	_, err := r.client.DeleteWebhook(ctx, data.Project.Value, data.Id.Value)
+
+	if err != nil && !isNotFound(err) {
+		resp.Diagnostics.Append(clientErrorDiagnosticf(err, "webhook %s could not be deleted", data.Id.Value))
+		return
+	}
+}
+
+func (r *WebhookResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	projectId, webhookId, _ := strings.Cut(req.ID, "/")
+	if projectId == "" || webhookId == "" {
+		resp.Diagnostics.AddError("Import Error", "The format for importing a webhook is project-id/webhook-id")
+		return
+	}
+
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), resource.ImportStateRequest{ID: webhookId}, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("project"), resource.ImportStateRequest{ID: projectId}, resp)
+}
+
+// toWebhook builds the API representation of the webhook from the model. The
+// headers, secret, filter, and projection are left out when they are null,
+// unless the prior state has a value: an update only changes the fields that
+// are sent, so they are sent empty to remove them. prior is nil on create.
+func (m *WebhookResourceModel) toWebhook(ctx context.Context, prior *WebhookResourceModel) (*Webhook, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	webhook := &Webhook{
+		Type:             m.Type.Value,
+		Name:             m.Name.Value,
+		URL:              m.URL.Value,
+		Dataset:          m.Dataset.Value,
+		IsDisabledByUser: m.IsDisabledByUser.Value,
+		ApiVersion:       m.ApiVersion.Value,
+		HttpMethod:       m.HttpMethod.Value,
+	}
+
+	if !m.Headers.Null && !m.Headers.Unknown {
+		headers := map[string]string{}
+		diags.Append(m.Headers.ElementsAs(ctx, &headers, false)...)
+		webhook.Headers = &headers
+	} else if prior != nil && len(prior.Headers.Elems) > 0 {
+		webhook.Headers = &map[string]string{}
+	}
+
+	var priorDescription, priorSecret types.String
+	if prior != nil {
+		priorDescription = prior.Description
+		priorSecret = prior.Secret
+	}
+	webhook.Description = clearableString(m.Description, priorDescription)
+	webhook.Secret = clearableString(m.Secret, priorSecret)
+
+	if len(m.Rule) > 0 {
+		var priorRule WebhookRuleModel
+		if prior != nil && len(prior.Rule) > 0 {
+			priorRule = prior.Rule[0]
+		}
+		rule := &WebhookRule{
+			Filter:     clearableString(m.Rule[0].Filter, priorRule.Filter),
+			Projection: clearableString(m.Rule[0].Projection, priorRule.Projection),
+		}
+		diags.Append(m.Rule[0].On.ElementsAs(ctx, &rule.On, false)...)
+		webhook.Rule = rule
+	}
+
+	return webhook, diags
+}
+
+// clearableString returns the value to send for an optional string field: the
+// planned value, an empty string if the field is null but the prior state has
+// a value, or nil to leave the field out.
+func clearableString(planned types.String, prior types.String) *string {
+	if !planned.Null && !planned.Unknown && planned.Value != "" {
+		return &planned.Value
+	}
+	if prior.Value != "" {
+		empty := ""
+		return &empty
+	}
+	return nil
+}
+
+// fromWebhook refreshes the model with the values returned by the API. The
+// secret is not returned, so the configured value is kept.
+func (m *WebhookResourceModel) fromWebhook(webhook *Webhook) {
+	m.Id = types.String{Value: webhook.Id}
+	m.Type = types.String{Value: webhook.Type}
+	m.Name = types.String{Value: webhook.Name}
+	m.URL = types.String{Value: webhook.URL}
+	m.Dataset = types.String{Value: webhook.Dataset}
+	m.Description = optionalString(webhook.Description)
+	m.IsDisabledByUser = types.Bool{Value: webhook.IsDisabledByUser}
+	m.ApiVersion = types.String{Value: webhook.ApiVersion}
+	m.HttpMethod = types.String{Value: webhook.HttpMethod}
+	if webhook.HttpMethod == "" {
+		m.HttpMethod = types.String{Value: "POST"}
+	}
+
+	m.Headers = types.Map{ElemType: types.StringType, Null: true}
+	if webhook.Headers != nil && len(*webhook.Headers) > 0 {
+		m.Headers = types.Map{ElemType: types.StringType, Elems: make(map[string]attr.Value, len(*webhook.Headers))}
+		for name, value := range *webhook.Headers {
+			m.Headers.Elems[name] = types.String{Value: value}
+		}
+	}
+
+	m.Rule = nil
+	if webhook.Rule != nil && len(webhook.Rule.On) > 0 {
+		on := types.List{ElemType: types.StringType}
+		for _, o := range webhook.Rule.On {
+			on.Elems = append(on.Elems, types.String{Value: o})
+		}
+		m.Rule = []WebhookRuleModel{
+			{
+				On:         on,
+				Filter:     optionalString(webhook.Rule.Filter),
+				Projection: optionalString(webhook.Rule.Projection),
+			},
+		}
+	}
+}
+
+// optionalString returns the value of an optional string field of the API,
+// or null if it is not set.
+func optionalString(value *string) types.String {
+	if value == nil || *value == "" {
+		return types.String{Null: true}
+	}
+	return types.String{Value: *value}
+}
@@ -0,0 +1,219 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestWebhookResource(t *testing.T) {
+	config := `
+resource "sanity_webhook" "test" {
+  project = "p1"
+  type    = "document"
+  name    = "Notify"
+  url     = "https://example.com/hook"
+  dataset = "production"
+
+  rule {
+    on = ["create"]
+  }
+}
+`
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "optional fields can be removed",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(`
+resource "sanity_webhook" "test" {
+  project = "p1"
+  type    = "document"
+  name    = "Notify"
+  url     = "https://example.com/hook"
+  dataset = "production"
+  secret  = "s3cr3t"
+
+  description = "Notifies the team"
+  headers = {
+    Authorization = "Bearer abc"
+  }
+
+  rule {
+    on         = ["create"]
+    filter     = "_type == 'post'"
+    projection = "{_id}"
+  }
+}
+`),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckResourceAttr("sanity_webhook.test", "description", "Notifies the team"),
+							resource.TestCheckResourceAttr("sanity_webhook.test", "headers.Authorization", "Bearer abc"),
+							resource.TestCheckResourceAttr("sanity_webhook.test", "rule.0.filter", "_type == 'post'"),
+							testCheckMockWebhook(m, "p1", "s3cr3t", "_type == 'post'", "{_id}", 1),
+						),
+					},
+					{
+						Config: m.providerConfig(config),
+						Check: resource.ComposeAggregateTestCheckFunc(
+							resource.TestCheckNoResourceAttr("sanity_webhook.test", "description"),
+							resource.TestCheckNoResourceAttr("sanity_webhook.test", "headers.%"),
+							resource.TestCheckNoResourceAttr("sanity_webhook.test", "secret"),
+							resource.TestCheckNoResourceAttr("sanity_webhook.test", "rule.0.filter"),
+							resource.TestCheckNoResourceAttr("sanity_webhook.test", "rule.0.projection"),
+							testCheckMockWebhook(m, "p1", "", "", "", 0),
+							testCheckMock(func() error {
+								var description *string
+								m.withProject("p1", func(p *mockProject) {
+									description = p.webhooks[0].Description
+								})
+								if description != nil && *description != "" {
+									return fmt.Errorf("expected the description to be cleared, got %q", *description)
+								}
+								return nil
+							}),
+						),
+					},
+				}
+			},
+		},
+		{
+			name: "externally deleted",
+			setup: func(m *mockSanity) {
+				m.addProject("p1", "Test")
+			},
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config: m.providerConfig(config),
+						Check:  resource.TestCheckResourceAttrSet("sanity_webhook.test", "id"),
+					},
+					{
+						PreConfig: func() {
+							m.withProject("p1", func(p *mockProject) {
+								p.webhooks = nil
+							})
+						},
+						RefreshState:       true,
+						ExpectNonEmptyPlan: true,
+					},
+					{
+						Config: m.providerConfig(config),
+						Check:  testCheckRequestCount(m, "POST", "/hooks/projects/p1", 2),
+					},
+				}
+			},
+		},
+	})
+}
+
+// testCheckMockWebhook checks the secret, filter, projection, and number of
+// headers of the only webhook of the project in the mock.
+func testCheckMockWebhook(m *mockSanity, projectId string, secret string, filter string, projection string, headers int) resource.TestCheckFunc {
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	return testCheckMock(func() error {
+		var webhooks []Webhook
+		m.withProject(projectId, func(p *mockProject) {
+			webhooks = append(webhooks, p.webhooks...)
+		})
+		if len(webhooks) != 1 {
+			return fmt.Errorf("expected 1 webhook, got %d", len(webhooks))
+		}
+
+		w := webhooks[0]
+		var n int
+		if w.Headers != nil {
+			n = len(*w.Headers)
+		}
+		var got [3]string
+		if w.Rule != nil {
+			got = [3]string{value(w.Secret), value(w.Rule.Filter), value(w.Rule.Projection)}
+		}
+		if want := [3]string{secret, filter, projection}; got != want || n != headers {
+			return fmt.Errorf("expected secret, filter and projection %q and %d headers, got %q and %d", want, headers, got, n)
+		}
+		return nil
+	})
+}
+
+func TestWebhookResource_validateConfig(t *testing.T) {
+	config := func(m *mockSanity, webhookType string, rule string) string {
+		return m.providerConfig(fmt.Sprintf(`
+resource "sanity_webhook" "test" {
+  project = "p1"
+  type    = %q
+  name    = "Notify"
+  url     = "https://example.com/hook"
+  dataset = "production"
+%s}
+`, webhookType, rule))
+	}
+
+	rule := func(on string) string {
+		return fmt.Sprintf(`
+  rule {
+    on = %s
+  }
+`, on)
+	}
+
+	runMockTestCases(t, []mockTestCase{
+		{
+			name: "valid configurations",
+			steps: func(m *mockSanity) []resource.TestStep {
+				return []resource.TestStep{
+					{
+						Config:             config(m, "document", rule(`["create", "update", "delete"]`)),
+						PlanOnly:           true,
+						ExpectNonEmptyPlan: true,
+					},
+					{
+						Config:             config(m, "transaction", ""),
+						PlanOnly:           true,
+						ExpectNonEmptyPlan: true,
+					},
+				}
+			},
+		},
+		{
+			name: "invalid configurations",
+			steps: func(m *mockSanity) []resource.TestStep {
+				cases := []struct {
+					webhookType string
+					rule        string
+					err         string
+				}{
+					{webhookType: "document", err: `A document webhook requires a rule block`},
+					{webhookType: "transaction", rule: rule(`["create"]`), err: `A rule cannot be set on a transaction webhook`},
+					{webhookType: "mutation", rule: rule(`["create"]`), err: `The webhook type must be "document" or "transaction", got:\s+"mutation"`},
+					{webhookType: "document", rule: rule(`[]`), err: `A document webhook must be triggered by at least one of`},
+					{webhookType: "document", rule: rule(`["create", "publish"]`), err: `The operation must be "create", "update", or "delete", got:\s+"publish"`},
+					{webhookType: "document", rule: rule(`["create", "create"]`), err: `The operation "create" is listed more than once`},
+				}
+
+				var steps []resource.TestStep
+				for _, tc := range cases {
+					steps = append(steps, resource.TestStep{
+						Config:      config(m, tc.webhookType, tc.rule),
+						PlanOnly:    true,
+						ExpectError: regexp.MustCompile(tc.err),
+					})
+				}
+				return steps
+			},
+		},
+	})
+}
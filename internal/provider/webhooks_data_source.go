@@ -0,0 +1,154 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces
+var _ datasource.DataSource = &WebhooksDataSource{}
+
+func NewWebhooksDataSource() datasource.DataSource {
+	return &WebhooksDataSource{}
+}
+
+// WebhooksDataSource defines the data source implementation.
+type WebhooksDataSource struct {
+	client *Client
+}
+
+// WebhooksDataSourceModel describes the data source data model.
+type WebhooksDataSourceModel struct {
+	Project  types.String                     `tfsdk:"project"`
+	Webhooks []WebhooksDataSourceWebhookModel `tfsdk:"webhooks"`
+}
+
+// WebhooksDataSourceWebhookModel describes a single webhook in the data source
+// data model.
+type WebhooksDataSourceWebhookModel struct {
+	Id         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	URL        types.String `tfsdk:"url"`
+	Dataset    types.String `tfsdk:"dataset"`
+	IsDisabled types.Bool   `tfsdk:"is_disabled"`
+}
+
+func (d *WebhooksDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_webhooks"
+}
+
+func (d *WebhooksDataSource) GetSchema(ctx context.Context) (tfsdk.Schema, diag.Diagnostics) {
+	return tfsdk.Schema{
+		MarkdownDescription: "Gets all the webhooks in a Sanity project, including webhooks created outside of Terraform. Webhook secrets are never returned.",
+
+		Attributes: map[string]tfsdk.Attribute{
+			"project": {
+				MarkdownDescription: "The ID of the project that the webhooks belong to.",
+				Type:                types.StringType,
+				Required:            true,
+			},
+			"webhooks": {
+				MarkdownDescription: "The webhooks in the project, sorted by ID.",
+				Computed:            true,
+				Attributes:          tfsdk.ListNestedAttributes(webhookSummaryAttributes()),
+			},
+		},
+	}, nil
+}
+
+// webhookSummaryAttributes describes the attributes of a webhook that are
+// returned by the webhook data sources.
+func webhookSummaryAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"id": {
+			MarkdownDescription: "The unique webhook ID generated by Sanity.",
+			Type:                types.StringType,
+			Computed:            true,
+		},
+		"name": {
+			MarkdownDescription: "The webhook name.",
+			Type:                types.StringType,
+			Computed:            true,
+		},
+		"url": {
+			MarkdownDescription: "The URL that receives the webhook requests.",
+			Type:                types.StringType,
+			Computed:            true,
+		},
+		"dataset": {
+			MarkdownDescription: "The name of the dataset the webhook listens to, or `*` for all datasets.",
+			Type:                types.StringType,
+			Computed:            true,
+		},
+		"is_disabled": {
+			MarkdownDescription: "Indicates whether the webhook is disabled.",
+			Type:                types.BoolType,
+			Computed:            true,
+		},
+	}
+}
+
+func (d *WebhooksDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*Client)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *provider.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.client = client
+}
+
+func (d *WebhooksDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WebhooksDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Project.Null {
+		resp.Diagnostics.AddError("Project is null", "Project is null")
+		return
+	}
+
+	webhooks, err := d.client.ListWebhooks(ctx, data.Project.Value)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostic(err))
+		return
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].Id < webhooks[j].Id
+	})
+
+	data.Webhooks = make([]WebhooksDataSourceWebhookModel, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		data.Webhooks = append(data.Webhooks, WebhooksDataSourceWebhookModel{
+			Id:         types.String{Value: webhook.Id},
+			Name:       types.String{Value: webhook.Name},
+			URL:        types.String{Value: webhook.URL},
+			Dataset:    types.String{Value: webhook.Dataset},
+			IsDisabled: types.Bool{Value: webhook.IsDisabledByUser},
+		})
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}